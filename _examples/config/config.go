@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sayden/log"
+	_ "github.com/sayden/log/handlers/json"
+	_ "github.com/sayden/log/handlers/level"
+	_ "github.com/sayden/log/handlers/multi"
+	_ "github.com/sayden/log/handlers/text"
+)
+
+func main() {
+	c, err := log.LoadConfig("config.yaml")
+	if err != nil {
+		log.WithError(err).Fatal("loading config")
+	}
+
+	if err := log.Configure(c.WithEnv()); err != nil {
+		log.WithError(err).Fatal("configuring")
+	}
+
+	ctx := log.WithFields(log.Fields{
+		"file": "something.png",
+		"type": "image/png",
+		"user": "tobi",
+	})
+
+	for range time.Tick(time.Millisecond * 200) {
+		ctx.Info("upload")
+		ctx.Info("upload complete")
+		ctx.Warn("upload retry")
+		ctx.WithError(errors.New("unauthorized")).Error("upload failed")
+	}
+}
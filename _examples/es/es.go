@@ -9,6 +9,7 @@ import (
 	"github.com/sayden/log"
 	"github.com/sayden/log/handlers/es"
 	"github.com/sayden/log/handlers/multi"
+	"github.com/sayden/log/handlers/sample"
 	"github.com/sayden/log/handlers/text"
 	"github.com/tj/go-elastic"
 )
@@ -26,7 +27,7 @@ func main() {
 
 	t := text.New(os.Stderr)
 
-	log.SetHandler(multi.New(e, t))
+	log.SetHandler(multi.New(sample.New(e, 10, 100, time.Second), t))
 
 	ctx := log.WithFields(log.Fields{
 		"file": "something.png",
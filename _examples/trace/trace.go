@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"os"
 	"time"
 
@@ -8,9 +9,9 @@ import (
 	"github.com/sayden/log/handlers/text"
 )
 
-func work(ctx log.Interface) (err error) {
+func work(ctx context.Context) (err error) {
 	path := "Readme.md"
-	defer ctx.WithField("path", path).Trace("opening").Stop(&err)
+	defer log.FromContext(ctx).WithField("path", path).Trace("opening").Stop(&err)
 	_, err = os.Open(path)
 	return
 }
@@ -18,10 +19,10 @@ func work(ctx log.Interface) (err error) {
 func main() {
 	log.SetHandler(text.New(os.Stderr))
 
-	ctx := log.WithFields(log.Fields{
+	ctx := log.NewContext(context.Background(), log.WithFields(log.Fields{
 		"app": "myapp",
 		"env": "prod",
-	})
+	}))
 
 	for range time.Tick(time.Second) {
 		_ = work(ctx)
@@ -0,0 +1,54 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time, to stamp entries and measure Trace durations.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock used when none is set, calling Now.
+var SystemClock Clock = systemClock{}
+
+// systemClock is the Clock of the system.
+type systemClock struct{}
+
+// Now implements Clock.
+func (systemClock) Now() time.Time {
+	return Now()
+}
+
+// FakeClock is a Clock for tests, which only moves when told to.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock set to `t`.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+// Now implements Clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Add moves the clock forward by `d`.
+func (c *FakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set sets the clock to `t`.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
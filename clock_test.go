@@ -0,0 +1,47 @@
+package log_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/memory"
+)
+
+func TestLogger_Clock(t *testing.T) {
+	t.Parallel()
+
+	h := memory.New()
+	clock := log.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+		Clock:   clock,
+	}
+
+	l.Named("db").Info("hello")
+
+	func() {
+		defer l.Trace("upload").Stop(nil)
+		clock.Add(1500 * time.Millisecond)
+	}()
+
+	assert.Equal(t, 3, len(h.Entries))
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), h.Entries[0].GetTimestamp())
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 1, 500e6, time.UTC), h.Entries[2].GetTimestamp())
+	assert.Equal(t, 1500*time.Millisecond, h.Entries[2].GetFields()["duration"])
+}
+
+func TestFakeClock(t *testing.T) {
+	t.Parallel()
+
+	clock := log.NewFakeClock(time.Unix(0, 0))
+	clock.Add(time.Minute)
+	assert.Equal(t, time.Unix(60, 0), clock.Now())
+
+	clock.Set(time.Unix(5, 0))
+	assert.Equal(t, time.Unix(5, 0), clock.Now())
+}
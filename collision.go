@@ -0,0 +1,92 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReservedKey is returned by handlers using CollisionError for fields
+// named after the keys they reserve.
+var ErrReservedKey = errors.New("reserved key")
+
+// CollisionPolicy is how flat encoders such as logfmt and papertrail,
+// which write the timestamp, level and message next to the fields, name
+// the fields colliding with those keys.
+type CollisionPolicy int
+
+// Collision policies.
+const (
+	// CollisionPrefix prefixes the field with "fields.", as in
+	// "fields.level", or suffixes it as CollisionSuffix when that key
+	// is used too.
+	CollisionPrefix CollisionPolicy = iota
+
+	// CollisionSuffix suffixes the field with "_" until it is unique,
+	// as in "level_".
+	CollisionSuffix
+
+	// CollisionError fails to handle the entry with ErrReservedKey.
+	CollisionError
+)
+
+// Rename returns the key for the field `name` of `fields`, which is
+// `name` itself unless it is one of the `reserved` keys.
+func (p CollisionPolicy) Rename(name string, fields Fields, reserved ...string) (string, error) {
+	if !isReserved(name, reserved) {
+		return name, nil
+	}
+
+	switch p {
+	case CollisionError:
+		return "", fmt.Errorf("%w %q", ErrReservedKey, name)
+	case CollisionPrefix:
+		key := "fields." + name
+		if !isTaken(key, fields, reserved) {
+			return key, nil
+		}
+	}
+
+	key := name + "_"
+	for isTaken(key, fields, reserved) {
+		key += "_"
+	}
+
+	return key, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing "prefix",
+// "suffix" and "error", for configurations.
+func (p *CollisionPolicy) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "prefix":
+		*p = CollisionPrefix
+	case "suffix":
+		*p = CollisionSuffix
+	case "error":
+		*p = CollisionError
+	default:
+		return fmt.Errorf("invalid collision policy %q", b)
+	}
+
+	return nil
+}
+
+// isReserved returns whether `name` is one of the `reserved` keys.
+func isReserved(name string, reserved []string) bool {
+	for _, r := range reserved {
+		if name == r {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTaken returns whether `key` is one of the `fields` or `reserved` keys.
+func isTaken(key string, fields Fields, reserved []string) bool {
+	if _, ok := fields[key]; ok {
+		return true
+	}
+
+	return isReserved(key, reserved)
+}
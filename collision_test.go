@@ -0,0 +1,36 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+)
+
+func TestCollisionPolicy_Rename(t *testing.T) {
+	fields := log.Fields{"level": "debug", "level_": "trace", "user": "tj"}
+	reserved := []string{"level", "message"}
+
+	k, err := log.CollisionPrefix.Rename("user", fields, reserved...)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", k)
+
+	k, err = log.CollisionPrefix.Rename("level", fields, reserved...)
+	assert.NoError(t, err)
+	assert.Equal(t, "fields.level", k)
+
+	k, err = log.CollisionSuffix.Rename("level", fields, reserved...)
+	assert.NoError(t, err)
+	assert.Equal(t, "level__", k)
+
+	fields["fields.level"] = "info"
+
+	k, err = log.CollisionPrefix.Rename("level", fields, reserved...)
+	assert.NoError(t, err)
+	assert.Equal(t, "level__", k)
+
+	_, err = log.CollisionError.Rename("level", fields, reserved...)
+	assert.ErrorIs(t, err, log.ErrReservedKey)
+	assert.EqualError(t, err, `reserved key "level"`)
+}
@@ -0,0 +1,275 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is a declarative logging setup, so sinks can be changed without
+// changing code. It may be read with LoadConfig or WithEnv, or decoded
+// with encoding/json or YAML, and is applied with Configure or NewLogger.
+type Config struct {
+	// Level is the minimum level logged, defaulting to "info".
+	Level string `json:"level,omitempty" yaml:"level"`
+
+	// Overrides are levels for named loggers, as in SetLevelOverrides.
+	Overrides string `json:"overrides,omitempty" yaml:"overrides"`
+
+	// Verbosity is the highest n for which V(n) entries are logged.
+	Verbosity int `json:"verbosity,omitempty" yaml:"verbosity"`
+
+	// Handler is the root of the handler tree, defaulting to the stdlib log.
+	Handler HandlerConfig `json:"handler" yaml:"handler"`
+}
+
+// HandlerConfig configures a handler of the tree, built by the factory
+// registered for its Type.
+//
+// For example the "multi" handler passes entries to all of its Handlers,
+// and the "level" handler to its only one from Level. The "json",
+// "logfmt", "text" and "cli" handlers write to Output, which is
+// "stderr", "stdout" or a file path. Other settings specific to a type
+// are in Options.
+type HandlerConfig struct {
+	Type     string                 `json:"type" yaml:"type"`
+	Level    string                 `json:"level,omitempty" yaml:"level"`
+	Output   string                 `json:"output,omitempty" yaml:"output"`
+	Handlers []HandlerConfig        `json:"handlers,omitempty" yaml:"handlers"`
+	Options  map[string]interface{} `json:"options,omitempty" yaml:"options"`
+}
+
+// HandlerFactory builds a handler from its configuration.
+type HandlerFactory func(HandlerConfig) (Handler, error)
+
+// factories registered by handler type.
+var factories = struct {
+	sync.RWMutex
+	m map[string]HandlerFactory
+}{m: map[string]HandlerFactory{}}
+
+// RegisterHandler makes the handler type `name` available to configurations,
+// panicking when it is already registered. The handler packages register
+// their types when imported, so import the ones used, for example:
+//
+//	import _ "github.com/sayden/log/handlers/json"
+func RegisterHandler(name string, f HandlerFactory) {
+	factories.Lock()
+	defer factories.Unlock()
+
+	if _, ok := factories.m[name]; ok {
+		panic(fmt.Sprintf("log: handler %q registered twice", name))
+	}
+
+	factories.m[name] = f
+}
+
+// BuildHandler returns the handler tree configured by `c`.
+func BuildHandler(c HandlerConfig) (Handler, error) {
+	factories.RLock()
+	f, ok := factories.m[c.Type]
+	factories.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown handler type %q, is its package imported?", c.Type)
+	}
+
+	h, err := f(c)
+	if err != nil {
+		return nil, fmt.Errorf("handler %q: %w", c.Type, err)
+	}
+
+	return h, nil
+}
+
+// Children returns the handlers built from Handlers.
+func (c HandlerConfig) Children() ([]Handler, error) {
+	handlers := make([]Handler, 0, len(c.Handlers))
+
+	for _, hc := range c.Handlers {
+		h, err := BuildHandler(hc)
+		if err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, h)
+	}
+
+	return handlers, nil
+}
+
+// Writer returns the writer for Output, opening files for appending.
+// Pass the handler writing to it through Closing, so files are closed.
+func (c HandlerConfig) Writer() (io.Writer, error) {
+	switch c.Output {
+	case "", "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	default:
+		return os.OpenFile(c.Output, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	}
+}
+
+// Closing returns `h`, which writes to `w` from Writer, closing `w`
+// along with `h` when it is a file.
+func (c HandlerConfig) Closing(h Handler, w io.Writer) Handler {
+	f, ok := w.(*os.File)
+	if !ok || f == os.Stdout || f == os.Stderr {
+		return h
+	}
+
+	return &fileHandler{Handler: h, file: f}
+}
+
+// fileHandler closes the file its handler writes to.
+type fileHandler struct {
+	Handler
+	file *os.File
+}
+
+// Flush implements Flusher.
+func (h *fileHandler) Flush() error {
+	return walk(h.Handler, flushHandler)
+}
+
+// Close implements Closer, closing the handler and then the file.
+func (h *fileHandler) Close() error {
+	err := walk(h.Handler, closeHandler)
+
+	if e := h.file.Close(); e != nil && err == nil {
+		err = e
+	}
+
+	return err
+}
+
+// Decode decodes Options into `v`, a struct with json tags, failing on
+// unknown options.
+func (c HandlerConfig) Decode(v interface{}) error {
+	b, err := json.Marshal(c.Options)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	return nil
+}
+
+// LoadConfig reads the Config in the JSON or YAML file at `path`, the
+// format depending on its extension.
+func LoadConfig(path string) (Config, error) {
+	var c Config
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(b, &c)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &c)
+	default:
+		err = fmt.Errorf("unknown config format %q", filepath.Ext(path))
+	}
+
+	if err != nil {
+		return c, fmt.Errorf("loading %s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// WithEnv returns the Config with the settings of the environment
+// variables which are set. LOG_LEVEL sets the Level. LOG_FORMAT and
+// LOG_OUTPUT replace the Handler with one of the type and output
+// given, defaulting to "logfmt" and "stderr".
+func (c Config) WithEnv() Config {
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		c.Level = v
+	}
+
+	format, output := os.Getenv("LOG_FORMAT"), os.Getenv("LOG_OUTPUT")
+	if format == "" && output == "" {
+		return c
+	}
+
+	if format == "" {
+		format = "logfmt"
+	}
+
+	c.Handler = HandlerConfig{Type: format, Output: output}
+	return c
+}
+
+// ConfigFromEnv returns the Config set by the environment, see WithEnv.
+func ConfigFromEnv() Config {
+	return Config{}.WithEnv()
+}
+
+// NewLogger returns a Logger configured by `c`.
+func NewLogger(c Config) (*Logger, error) {
+	level := LevelInfo
+
+	if c.Level != "" {
+		l, err := ParseLevel(c.Level)
+		if err != nil {
+			return nil, err
+		}
+		level = l
+	}
+
+	var h Handler = HandlerFunc(handleStdLog)
+
+	if c.Handler.Type != "" {
+		var err error
+		if h, err = BuildHandler(c.Handler); err != nil {
+			return nil, err
+		}
+	}
+
+	l := &Logger{
+		Handler:   h,
+		Level:     level,
+		Verbosity: int32(c.Verbosity),
+	}
+
+	if err := l.SetLevelOverrides(c.Overrides); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Configure applies `c` to Log, keeping its Telemetry. The previous
+// handler is not closed.
+func Configure(c Config) error {
+	l, err := NewLogger(c)
+	if err != nil {
+		return err
+	}
+
+	logger, ok := Log.(*Logger)
+	if !ok {
+		return nil
+	}
+
+	logger.root().overrides.Store(l.overrides.Load())
+	logger.SetLevel(l.Level)
+	logger.SetVerbosity(c.Verbosity)
+	logger.SwapHandler(l.Handler)
+	return nil
+}
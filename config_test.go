@@ -0,0 +1,187 @@
+package log_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	_ "github.com/sayden/log/handlers/json"
+	_ "github.com/sayden/log/handlers/level"
+	_ "github.com/sayden/log/handlers/logfmt"
+	_ "github.com/sayden/log/handlers/multi"
+)
+
+func write(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func read(t *testing.T, path string) string {
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	errors := filepath.Join(dir, "errors.log")
+	all := filepath.Join(dir, "all.log")
+
+	yaml := write(t, "log.yaml", `
+level: debug
+overrides: db=warn
+handler:
+  type: multi
+  handlers:
+    - type: level
+      level: error
+      handlers:
+        - type: json
+          output: `+errors+`
+    - type: logfmt
+      output: `+all+`
+      options:
+        collisions: suffix
+`)
+
+	json := write(t, "log.json", `{
+  "level": "debug",
+  "overrides": "db=warn",
+  "handler": {
+    "type": "multi",
+    "handlers": [
+      {"type": "level", "level": "error", "handlers": [{"type": "json", "output": "`+errors+`"}]},
+      {"type": "logfmt", "output": "`+all+`", "options": {"collisions": "suffix"}}
+    ]
+  }
+}`)
+
+	for _, path := range []string{yaml, json} {
+		assert.NoError(t, os.RemoveAll(errors))
+		assert.NoError(t, os.RemoveAll(all))
+
+		c, err := log.LoadConfig(path)
+		assert.NoError(t, err)
+
+		l, err := log.NewLogger(c)
+		assert.NoError(t, err)
+
+		l.Debug("debug")
+		l.Named("db").Info("query")
+		l.WithField("level", "custom").Error("boom")
+
+		assert.Equal(t, 1, strings.Count(read(t, errors), "\n"))
+		assert.Contains(t, read(t, errors), `"message":"boom"`)
+
+		lines := strings.Split(strings.TrimSpace(read(t, all)), "\n")
+		assert.Equal(t, 2, len(lines))
+		assert.Contains(t, lines[0], "message=debug")
+		assert.Contains(t, lines[1], "message=boom level_=custom")
+	}
+}
+
+func TestLoadConfig_errors(t *testing.T) {
+	_, err := log.LoadConfig(write(t, "log.toml", ""))
+	assert.Contains(t, err.Error(), `unknown config format ".toml"`)
+
+	_, err = log.LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	cases := []struct {
+		config log.Config
+		err    string
+	}{
+		{log.Config{Level: "loud"}, "invalid level"},
+		{log.Config{Handler: log.HandlerConfig{Type: "carrier-pigeon"}}, `unknown handler type "carrier-pigeon"`},
+		{log.Config{Handler: log.HandlerConfig{Type: "json", Options: map[string]interface{}{"pretty": true}}}, `handler "json": invalid options`},
+		{log.Config{Handler: log.HandlerConfig{Type: "level", Level: "warn"}}, `handler "level": expected one handler, got 0`},
+		{log.Config{Overrides: "db"}, "invalid level override"},
+	}
+
+	for _, c := range cases {
+		_, err := log.NewLogger(c.config)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), c.err)
+		}
+	}
+}
+
+func TestConfig_WithEnv(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.log")
+
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_FORMAT", "json")
+	t.Setenv("LOG_OUTPUT", out)
+
+	c := log.Config{
+		Level:   "debug",
+		Handler: log.HandlerConfig{Type: "multi"},
+	}.WithEnv()
+
+	assert.Equal(t, log.Config{
+		Level:   "warn",
+		Handler: log.HandlerConfig{Type: "json", Output: out},
+	}, c)
+
+	assert.Equal(t, c, log.ConfigFromEnv())
+
+	l, err := log.NewLogger(c)
+	assert.NoError(t, err)
+
+	l.Info("hello")
+	l.Warn("world")
+
+	assert.Contains(t, read(t, out), `"message":"world"`)
+	assert.NotContains(t, read(t, out), `"message":"hello"`)
+}
+
+func TestBuildHandler_close(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.log")
+
+	h, err := log.BuildHandler(log.HandlerConfig{
+		Type:     "multi",
+		Handlers: []log.HandlerConfig{{Type: "json", Output: out}},
+	})
+	assert.NoError(t, err)
+
+	l := &log.Logger{Handler: h, Level: log.LevelInfo}
+	l.Info("hello")
+
+	assert.NoError(t, l.Close(context.Background()))
+	assert.Contains(t, read(t, out), `"message":"hello"`)
+
+	// the file is closed
+	assert.Error(t, l.Close(context.Background()))
+}
+
+func TestConfigure(t *testing.T) {
+	logger := log.Log.(*log.Logger)
+	prev := logger.SwapHandler(nil)
+	defer logger.SwapHandler(prev)
+	defer logger.SetLevel(logger.GetLevel())
+	defer logger.SetLevelOverrides("")
+
+	out := filepath.Join(t.TempDir(), "out.log")
+
+	err := log.Configure(log.Config{
+		Level:     "error",
+		Overrides: "db=debug",
+		Handler:   log.HandlerConfig{Type: "logfmt", Output: out},
+	})
+	assert.NoError(t, err)
+
+	log.Warn("hello")
+	log.Error("world")
+
+	assert.Contains(t, read(t, out), "message=world")
+	assert.NotContains(t, read(t, out), "message=hello")
+	assert.True(t, log.Enabled(log.LevelError))
+	assert.False(t, log.Enabled(log.LevelWarn))
+	assert.True(t, logger.Named("db").Enabled(log.LevelDebug))
+}
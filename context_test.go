@@ -0,0 +1,44 @@
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey struct{}
+
+func TestFromContext(t *testing.T) {
+	ctx := context.Background()
+
+	logger := log.FromContext(ctx)
+	assert.Equal(t, log.Log, logger)
+
+	logs := log.WithField("foo", "bar")
+	ctx = log.NewContext(ctx, logs)
+
+	logger = log.FromContext(ctx)
+	assert.Equal(t, logs, logger)
+}
+
+func TestEntry_WithContext(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "abc")
+
+	l.WithContext(ctx).WithField("file", "sloth.png").Info("upload")
+	l.Info("upload complete")
+
+	assert.Equal(t, 2, len(h.Entries))
+	assert.Equal(t, "abc", h.Entries[0].GetContext().Value(ctxKey{}))
+	assert.Equal(t, log.Fields{"file": "sloth.png"}, h.Entries[0].GetFields())
+	assert.Equal(t, context.Background(), h.Entries[1].GetContext())
+}
@@ -3,7 +3,6 @@ package log
 import (
 	"bytes"
 	"fmt"
-	"log"
 	"sort"
 )
 
@@ -20,7 +19,8 @@ func (a byName) Len() int           { return len(a) }
 func (a byName) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a byName) Less(i, j int) bool { return a[i].Name < a[j].Name }
 
-// handleStdLog outpouts to the stlib log.
+// handleStdLog outpouts to the stlib log, or to its previous output
+// while RedirectStdLog is in effect.
 func handleStdLog(e Interface) error {
 	level := levelNames[e.GetLevel()]
 
@@ -39,7 +39,7 @@ func handleStdLog(e Interface) error {
 		fmt.Fprintf(&b, " %s=%v", f.Name, f.Value)
 	}
 
-	log.Println(b.String())
+	std().Println(b.String())
 
 	return nil
 }
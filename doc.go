@@ -6,5 +6,8 @@ to handlers.
 You may use this package with inline handlers, much like Logrus, however a centralized solution
 is recommended so that apps do not need to be re-deployed to add or remove logging service
 providers.
+
+The level and handler tree may also be set up from a JSON or YAML file, or the LOG_LEVEL,
+LOG_FORMAT and LOG_OUTPUT environment variables, with Configure. See Config.
 */
 package log
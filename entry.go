@@ -1,8 +1,11 @@
 package log
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
-	"os"
+	"math/rand"
 	"strings"
 	"time"
 )
@@ -10,37 +13,94 @@ import (
 // assert interface compliance.
 var _ Interface = (*Entry)(nil)
 
-// Now returns the current time.
+// Now returns the current time, used by SystemClock.
 var Now = time.Now
 
 // Entry represents a single log entry.
 type Entry struct {
-	Logger    *Logger   `json:"-"`
-	Fields    Fields    `json:"fields"`
-	Level     Level     `json:"level"`
-	Timestamp time.Time `json:"timestamp"`
-	Message   string    `json:"message"`
+	Logger    *Logger         `json:"-"`
+	Fields    Fields          `json:"fields"`
+	Level     Level           `json:"level"`
+	Timestamp time.Time       `json:"timestamp"`
+	Message   string          `json:"message"`
+	Context   context.Context `json:"-"`
+
+	// Names is the order of the Fields with the Logger's OrderedFields,
+	// nil when they are sorted. See GetFieldNames.
+	Names []string `json:"-"`
+
 	start     time.Time
-	fields    []Fields
-	t         Telemetry
+	threshold time.Duration
+	span      string
+	disabled  bool
+	fields    []Field
+	tags      []string
+	telemetry Telemetry
 }
 
-// NewEntry returns a new entry for `log`.
+// NewEntry returns a new entry for `log`, incrementing counters on `t`,
+// or on the Telemetry of `log` when `t` is nil.
 func NewEntry(log *Logger, t Telemetry) Interface {
 	return &Entry{
-		Logger: log,
-		t:      t,
+		Logger:    log,
+		telemetry: t,
+	}
+}
+
+// WithTags returns a new entry with `tags` added to the ones used by Inc.
+func (e *Entry) WithTags(tags ...string) Interface {
+	t := []string{}
+	t = append(t, e.tags...)
+	t = append(t, tags...)
+
+	v := e.derive()
+	v.tags = t
+	return v
+}
+
+// derive returns a copy of `e` to add to, keeping its span so derived
+// entries may still Stop it.
+func (e *Entry) derive() *Entry {
+	return &Entry{
+		Logger:    e.Logger,
+		Message:   e.Message,
+		Context:   e.Context,
+		start:     e.start,
+		threshold: e.threshold,
+		span:      e.span,
+		disabled:  e.disabled,
+		fields:    e.fields,
+		tags:      e.tags,
+		telemetry: e.telemetry,
 	}
 }
 
+// Inc increments the counter `n` by `v` with the entry's tags, returning
+// the entry so fields are kept.
 func (e *Entry) Inc(n string, v float64) Interface {
-	//first field value
-	e.t.Inc(n, v)
+	t := e.GetTelemetry()
+	if t == nil {
+		return e
+	}
+
+	if err := t.Inc(n, v, e.tags...); err != nil {
+		std().Printf("error incrementing %s: %s", n, err)
+	}
+
 	return e
 }
 
+// GetTelemetry returns the Telemetry of the entry, or of its Logger.
 func (e *Entry) GetTelemetry() Telemetry {
-	return e.t
+	if e.telemetry != nil {
+		return e.telemetry
+	}
+
+	if e.Logger == nil {
+		return nil
+	}
+
+	return e.Logger.GetTelemetry()
 }
 
 func (e *Entry) GetTimestamp() time.Time {
@@ -59,6 +119,93 @@ func (e *Entry) GetFields() Fields {
 	return e.Fields
 }
 
+// GetFieldNames returns the names of the Fields in the order they were
+// added with the Logger's OrderedFields, or sorted. Fields not in Names,
+// such as the ones set by hooks, follow sorted.
+func (e *Entry) GetFieldNames() []string {
+	if e.Names == nil {
+		return e.Fields.Names()
+	}
+
+	names := make([]string, 0, len(e.Fields))
+	seen := make(map[string]bool, len(e.Names))
+
+	for _, name := range e.Names {
+		if _, ok := e.Fields[name]; ok && !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+
+	if len(names) == len(e.Fields) {
+		return names
+	}
+
+	for _, name := range e.Fields.Names() {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// MarshalJSON implements json.Marshaler, encoding the Fields in the
+// order of GetFieldNames when it is kept.
+func (e *Entry) MarshalJSON() ([]byte, error) {
+	type entry Entry
+
+	if e.Names == nil {
+		return json.Marshal((*entry)(e))
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`{"fields":{`)
+
+	for i, name := range e.GetFieldNames() {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		k, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := json.Marshal(e.Fields[name])
+		if err != nil {
+			return nil, err
+		}
+
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+
+	rest, err := json.Marshal(struct {
+		Level     Level     `json:"level"`
+		Timestamp time.Time `json:"timestamp"`
+		Message   string    `json:"message"`
+	}{e.Level, e.Timestamp, e.Message})
+	if err != nil {
+		return nil, err
+	}
+
+	b.WriteString("},")
+	b.Write(rest[1:])
+	return b.Bytes(), nil
+}
+
+// GetContext returns the context attached with WithContext, or
+// context.Background() when there is none.
+func (e *Entry) GetContext() context.Context {
+	if e.Context == nil {
+		return context.Background()
+	}
+
+	return e.Context
+}
+
 func (e *Entry) SetMessage(msg string) {
 	e.Message = msg
 }
@@ -69,29 +216,52 @@ func (e *Entry) setStart(t time.Time) {
 
 // WithFields returns a new entry with `fields` set.
 func (e *Entry) WithFields(fields Fielder) Interface {
-	f := []Fields{}
+	return e.With(Field{kind: fieldsKind, ptr: fields.Fields()})
+}
+
+// With returns a new entry with the typed `fields` set.
+func (e *Entry) With(fields ...Field) Interface {
+	f := make([]Field, 0, len(e.fields)+len(fields))
 	f = append(f, e.fields...)
-	f = append(f, fields.Fields())
-	return &Entry{
-		Logger: e.Logger,
-		fields: f,
-		t:      e.t,
-	}
+	f = append(f, fields...)
+
+	v := e.derive()
+	v.fields = f
+	return v
+}
+
+// WithContext returns a new entry carrying `ctx`, which handlers may
+// read through GetContext, for example to pull deadlines or trace IDs.
+func (e *Entry) WithContext(ctx context.Context) Interface {
+	v := e.derive()
+	v.Context = ctx
+	return v
 }
 
 // WithField returns a new entry with the `key` and `value` set.
 func (e *Entry) WithField(key string, value interface{}) Interface {
-	return e.WithFields(Fields{key: value})
+	return e.With(Any(key, value))
 }
 
 // WithError returns a new entry with the "error" set to `err`.
 //
-// The given error may implement .Fielder, if it does the method
-// will add all its `.Fields()` into the returned entry.
+// The chain of errors wrapped by `err` is walked, including the ones
+// joined with errors.Join. Any of them may implement .Fielder, if they
+// do the method will add all their `.Fields()` into the returned entry,
+// the outer errors taking precedence. The "error_type" field lists the
+// types of the errors in the chain, and the "source" field is set from
+// the first error with a stack trace. With the Logger's ErrorStack the
+// "stack" field is set to the ErrorChain of `err`.
 func (e *Entry) WithError(err error) Interface {
-	ctx := e.WithField("error", err.Error())
+	chain := unwrap(err)
+	ctx := e.WithField("error", err.Error()).WithField("error_type", errorTypes(chain))
+
+	for _, err := range chain {
+		s, ok := err.(stackTracer)
+		if !ok || len(s.StackTrace()) == 0 {
+			continue
+		}
 
-	if s, ok := err.(stackTracer); ok {
 		frame := s.StackTrace()[0]
 
 		name := fmt.Sprintf("%n", frame)
@@ -104,15 +274,53 @@ func (e *Entry) WithError(err error) Interface {
 		}
 
 		ctx = ctx.WithField("source", fmt.Sprintf("%s: %s:%s", name, file, line))
+		break
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if f, ok := chain[i].(Fielder); ok {
+			ctx = ctx.WithFields(f.Fields())
+		}
 	}
 
-	if f, ok := err.(Fielder); ok {
-		ctx = ctx.WithFields(f.Fields())
+	if e.Logger != nil && e.Logger.root().ErrorStack {
+		ctx = ctx.WithField("stack", newErrorChain(chain))
 	}
 
 	return ctx
 }
 
+// Enabled returns whether messages at `level` are logged, so callers can
+// skip building expensive ones.
+func (e *Entry) Enabled(level Level) bool {
+	if e.disabled || e.Logger == nil {
+		return false
+	}
+
+	return e.Logger.Enabled(level)
+}
+
+// V returns a new entry which only logs when `n` is at most the
+// Logger's Verbosity, to grade chatty output as with glog.
+func (e *Entry) V(n int) Interface {
+	v := e.With().(*Entry)
+	v.disabled = e.disabled || e.Logger == nil || int32(n) > e.Logger.GetVerbosity()
+	return v
+}
+
+// Log message at `level`. Fatal and Panic levels exit and panic as their
+// methods do, and Log is the only way to write Trace level messages.
+func (e *Entry) Log(level Level, msg string) {
+	switch level {
+	case LevelPanic:
+		e.Panic(msg)
+	case LevelFatal:
+		e.Fatal(msg)
+	default:
+		e.Logger.log(level, e, msg)
+	}
+}
+
 // Debug level message.
 func (e *Entry) Debug(msg string) {
 	e.Logger.log(LevelDebug, e, msg)
@@ -123,6 +331,11 @@ func (e *Entry) Info(msg string) {
 	e.Logger.log(LevelInfo, e, msg)
 }
 
+// Notice level message.
+func (e *Entry) Notice(msg string) {
+	e.Logger.log(LevelNotice, e, msg)
+}
+
 // Warn level message.
 func (e *Entry) Warn(msg string) {
 	e.Logger.log(LevelWarn, e, msg)
@@ -133,77 +346,273 @@ func (e *Entry) Error(msg string) {
 	e.Logger.log(LevelError, e, msg)
 }
 
+// Critical level message.
+func (e *Entry) Critical(msg string) {
+	e.Logger.log(LevelCritical, e, msg)
+}
+
+// Panic level message, followed by a panic.
+func (e *Entry) Panic(msg string) {
+	e.Logger.log(LevelPanic, e, msg)
+	panic(msg)
+}
+
 // Fatal level message, followed by an exit.
 func (e *Entry) Fatal(msg string) {
 	e.Logger.log(LevelFatal, e, msg)
-	os.Exit(1)
+	e.Logger.exit(1)
+}
+
+// Logf formatted message at `level`.
+func (e *Entry) Logf(level Level, msg string, v ...interface{}) {
+	if level < LevelPanic && !e.Enabled(level) {
+		return
+	}
+
+	e.Log(level, fmt.Sprintf(msg, v...))
 }
 
 // Debugf level formatted message.
 func (e *Entry) Debugf(msg string, v ...interface{}) {
+	if !e.Enabled(LevelDebug) {
+		return
+	}
+
 	e.Debug(fmt.Sprintf(msg, v...))
 }
 
 // Infof level formatted message.
 func (e *Entry) Infof(msg string, v ...interface{}) {
+	if !e.Enabled(LevelInfo) {
+		return
+	}
+
 	e.Info(fmt.Sprintf(msg, v...))
 }
 
+// Noticef level formatted message.
+func (e *Entry) Noticef(msg string, v ...interface{}) {
+	if !e.Enabled(LevelNotice) {
+		return
+	}
+
+	e.Notice(fmt.Sprintf(msg, v...))
+}
+
 // Warnf level formatted message.
 func (e *Entry) Warnf(msg string, v ...interface{}) {
+	if !e.Enabled(LevelWarn) {
+		return
+	}
+
 	e.Warn(fmt.Sprintf(msg, v...))
 }
 
 // Errorf level formatted message.
 func (e *Entry) Errorf(msg string, v ...interface{}) {
+	if !e.Enabled(LevelError) {
+		return
+	}
+
 	e.Error(fmt.Sprintf(msg, v...))
 }
 
+// Criticalf level formatted message.
+func (e *Entry) Criticalf(msg string, v ...interface{}) {
+	if !e.Enabled(LevelCritical) {
+		return
+	}
+
+	e.Critical(fmt.Sprintf(msg, v...))
+}
+
+// Panicf level formatted message, followed by a panic.
+func (e *Entry) Panicf(msg string, v ...interface{}) {
+	e.Panic(fmt.Sprintf(msg, v...))
+}
+
 // Fatalf level formatted message, followed by an exit.
 func (e *Entry) Fatalf(msg string, v ...interface{}) {
 	e.Fatal(fmt.Sprintf(msg, v...))
 }
 
+// LogKV message at `level` with the alternating keys and values of `kv`,
+// such as "user", "tobi", "size", 1024. Bad keys are set in the BadKey field.
+func (e *Entry) LogKV(level Level, msg string, kv ...interface{}) {
+	e.With(kvFields(kv)...).Log(level, msg)
+}
+
+// DebugKV level message with key-value fields.
+func (e *Entry) DebugKV(msg string, kv ...interface{}) {
+	e.With(kvFields(kv)...).Debug(msg)
+}
+
+// InfoKV level message with key-value fields.
+func (e *Entry) InfoKV(msg string, kv ...interface{}) {
+	e.With(kvFields(kv)...).Info(msg)
+}
+
+// NoticeKV level message with key-value fields.
+func (e *Entry) NoticeKV(msg string, kv ...interface{}) {
+	e.With(kvFields(kv)...).Notice(msg)
+}
+
+// WarnKV level message with key-value fields.
+func (e *Entry) WarnKV(msg string, kv ...interface{}) {
+	e.With(kvFields(kv)...).Warn(msg)
+}
+
+// ErrorKV level message with key-value fields.
+func (e *Entry) ErrorKV(msg string, kv ...interface{}) {
+	e.With(kvFields(kv)...).Error(msg)
+}
+
+// CriticalKV level message with key-value fields.
+func (e *Entry) CriticalKV(msg string, kv ...interface{}) {
+	e.With(kvFields(kv)...).Critical(msg)
+}
+
+// PanicKV level message with key-value fields, followed by a panic.
+func (e *Entry) PanicKV(msg string, kv ...interface{}) {
+	e.With(kvFields(kv)...).Panic(msg)
+}
+
+// FatalKV level message with key-value fields, followed by an exit.
+func (e *Entry) FatalKV(msg string, kv ...interface{}) {
+	e.With(kvFields(kv)...).Fatal(msg)
+}
+
 // Trace returns a new entry with a Stop method to fire off
 // a corresponding completion log, useful with defer.
+//
+// The entry starts a span, setting the "span_id" field, and the
+// "parent_span_id" field when `e` descends from another Trace, so
+// the call tree can be rebuilt from the logs.
 func (e *Entry) Trace(msg string) Interface {
-	e.Info(msg)
-	v := e.WithFields(e.Fields)
-	v.SetMessage(msg)
-	v.setStart(time.Now())
+	v := e.newSpan(msg)
+	v.Info(msg)
+	return v
+}
+
+// TraceSlow is like Trace, but only logs the completion message when the
+// span takes `threshold` or longer, or fails. The start message is not
+// logged, so fast operations are silent.
+func (e *Entry) TraceSlow(msg string, threshold time.Duration) Interface {
+	v := e.newSpan(msg)
+	v.threshold = threshold
+	return v
+}
+
+// newSpan returns a new entry for a span nested in the one of `e`.
+func (e *Entry) newSpan(msg string) *Entry {
+	id := fmt.Sprintf("%016x", rand.Uint64())
+
+	fields := []Field{String("span_id", id)}
+	if e.span != "" {
+		fields = append(fields, String("parent_span_id", e.span))
+	}
+
+	v := e.With(fields...).(*Entry)
+	v.span = id
+	v.Message = msg
+	v.start = e.clock().Now()
+	v.threshold = 0
 	return v
 }
 
 // Stop should be used with Trace, to fire off the completion message. When
 // an `err` is passed the "error" field is set, and the log level is error.
 func (e *Entry) Stop(err *error) {
+	d := e.clock().Now().Sub(e.start)
+
 	if err == nil || *err == nil {
-		e.WithField("duration", time.Since(e.start)).Info(e.Message)
+		if d < e.threshold {
+			return
+		}
+
+		e.WithField("duration", d).Info(e.Message)
 	} else {
-		e.WithField("duration", time.Since(e.start)).WithError(*err).Error(e.Message)
+		e.WithField("duration", d).WithError(*err).Error(e.Message)
 	}
 }
 
-// mergedFields returns the fields list collapsed into a single map.
+// clock returns the Clock of the entry's Logger.
+func (e *Entry) clock() Clock {
+	if e.Logger == nil {
+		return SystemClock
+	}
+
+	return e.Logger.clock()
+}
+
+// mergedFields returns the fields list collapsed into a single map,
+// with the Lazy values evaluated and the Valuers rendered.
 func (e *Entry) mergedFields() Fields {
+	f, _ := e.merge(false)
+	return f
+}
+
+// merge returns the merged fields, and with `ordered` their names in the
+// order they were first added. The keys of a Fields map are added sorted.
+func (e *Entry) merge(ordered bool) (Fields, []string) {
 	f := Fields{}
 
-	for _, fields := range e.fields {
-		for k, v := range fields {
-			f[k] = v
+	var names *[]string
+	if ordered {
+		names = &[]string{}
+	}
+
+	for _, field := range e.fields {
+		if field.kind != fieldsKind {
+			setField(f, names, field.Key, field.Value())
+			continue
+		}
+
+		m := field.ptr.(Fields)
+
+		if !ordered {
+			for k, v := range m {
+				setField(f, nil, k, v)
+			}
+			continue
+		}
+
+		for _, k := range m.Names() {
+			setField(f, names, k, m[k])
 		}
 	}
 
-	return f
+	if names == nil {
+		return f, nil
+	}
+
+	return f, *names
 }
 
-// finalize returns a copy of the Entry with Fields merged.
+// finalize returns a copy of the Entry with Fields merged, the "logger"
+// field set for named loggers and the "caller" field for AddCaller.
 func (e *Entry) finalize(level Level, msg string) Interface {
+	fields, names := e.merge(e.Logger != nil && e.Logger.root().OrderedFields)
+
+	if e.Logger != nil && e.Logger.name != "" {
+		if _, ok := fields["logger"]; !ok {
+			fields["logger"] = e.Logger.name
+		}
+	}
+
+	if e.Logger != nil && e.Logger.root().AddCaller {
+		fields["caller"] = caller(e.Logger.root().CallerSkip)
+	}
+
 	return &Entry{
 		Logger:    e.Logger,
-		Fields:    e.mergedFields(),
+		Fields:    fields,
 		Level:     level,
 		Message:   msg,
-		Timestamp: Now(),
+		Names:     names,
+		Timestamp: e.clock().Now(),
+		tags:      e.tags,
+		telemetry: e.telemetry,
+		Context:   e.Context,
 	}
 }
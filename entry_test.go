@@ -1,14 +1,16 @@
 package log
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
+	pkgerrors "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestEntry_WithFields(t *testing.T) {
-	a := NewEntry(nil)
+	a := NewEntry(nil, nil)
 	assert.Nil(t, a.GetFields())
 
 	b := a.WithFields(Fields{"foo": "bar"})
@@ -25,29 +27,121 @@ func TestEntry_WithFields(t *testing.T) {
 }
 
 func TestEntry_WithField(t *testing.T) {
-	a := NewEntry(nil)
+	a := NewEntry(nil, nil)
 	b := a.WithField("foo", "bar")
 	assert.Equal(t, Fields{}, a.mergedFields())
 	assert.Equal(t, Fields{"foo": "bar"}, b.mergedFields())
 }
 
 func TestEntry_WithError(t *testing.T) {
-	a := NewEntry(nil)
+	a := NewEntry(nil, nil)
 	b := a.WithError(fmt.Errorf("boom"))
 	assert.Equal(t, Fields{}, a.mergedFields())
-	assert.Equal(t, Fields{"error": "boom"}, b.mergedFields())
+	assert.Equal(t, Fields{
+		"error":      "boom",
+		"error_type": "*errors.errorString",
+	}, b.mergedFields())
 }
 
 func TestEntry_WithErrorFields(t *testing.T) {
-	a := NewEntry(nil)
+	a := NewEntry(nil, nil)
 	b := a.WithError(errFields("boom"))
 	assert.Equal(t, Fields{}, a.mergedFields())
 	assert.Equal(t, Fields{
-		"error":  "boom",
-		"reason": "timeout",
+		"error":      "boom",
+		"error_type": "log.errFields",
+		"reason":     "timeout",
 	}, b.mergedFields())
 }
 
+func TestEntry_WithError_wrapped(t *testing.T) {
+	a := NewEntry(nil, nil)
+	err := fmt.Errorf("uploading: %w", errFields("boom"))
+	b := a.WithError(err)
+	assert.Equal(t, Fields{
+		"error":      "uploading: boom",
+		"error_type": "*fmt.wrapError > log.errFields",
+		"reason":     "timeout",
+	}, b.mergedFields())
+}
+
+func TestEntry_WithError_joined(t *testing.T) {
+	a := NewEntry(nil, nil)
+	err := fmt.Errorf("uploading: %w", errors.Join(errFields("boom"), errUser("tobi")))
+	b := a.WithError(err)
+	assert.Equal(t, Fields{
+		"error":      "uploading: boom\ntobi",
+		"error_type": "*fmt.wrapError > *errors.joinError > log.errFields > log.errUser",
+		"reason":     "timeout",
+		"user":       "tobi",
+	}, b.mergedFields())
+}
+
+func TestEntry_WithError_precedence(t *testing.T) {
+	a := NewEntry(nil, nil)
+	err := errReason{"retry", errFields("boom")}
+	b := a.WithError(err)
+	assert.Equal(t, "retry", b.mergedFields()["reason"])
+}
+
+func TestEntry_WithError_source(t *testing.T) {
+	a := NewEntry(nil, nil)
+	err := fmt.Errorf("uploading: %w", pkgerrors.New("boom"))
+	b := a.WithError(err)
+	assert.Contains(t, b.mergedFields()["source"], "TestEntry_WithError_source: ")
+	assert.Contains(t, b.mergedFields()["source"], "entry_test.go:")
+	assert.Nil(t, b.mergedFields()["stack"])
+}
+
+func TestEntry_WithError_stack(t *testing.T) {
+	a := NewEntry(&Logger{ErrorStack: true}, nil)
+	err := fmt.Errorf("uploading: %w", pkgerrors.Wrap(errFields("boom"), "putting"))
+	b := a.WithError(err)
+
+	chain, ok := b.mergedFields()["stack"].(ErrorChain)
+	assert.True(t, ok)
+	assert.Equal(t, 4, len(chain))
+
+	assert.Equal(t, "*fmt.wrapError", chain[0].Type)
+	assert.Equal(t, "uploading: putting: boom", chain[0].Message)
+	assert.Empty(t, chain[0].Stack)
+
+	assert.Equal(t, "*errors.withStack", chain[1].Type)
+	assert.NotEmpty(t, chain[1].Stack)
+	assert.Equal(t, "github.com/sayden/log.TestEntry_WithError_stack", chain[1].Stack[0].Function)
+
+	assert.Equal(t, "*errors.withMessage", chain[2].Type)
+	assert.Equal(t, "log.errFields", chain[3].Type)
+	assert.Equal(t, "timeout", b.mergedFields()["reason"])
+}
+
+type errUser string
+
+func (eu errUser) Error() string {
+	return string(eu)
+}
+
+func (eu errUser) Fields() Fields {
+	return Fields{"user": string(eu)}
+}
+
+type errReason struct {
+	reason string
+	err    error
+}
+
+func (er errReason) Error() string {
+	return er.err.Error()
+}
+
+func (er errReason) Unwrap() error {
+	return er.err
+}
+
+func (er errReason) Fields() Fields {
+	return Fields{"reason": er.reason}
+}
+
 type errFields string
 
 func (ef errFields) Error() string {
@@ -0,0 +1,119 @@
+package log
+
+import (
+	"math"
+	"time"
+)
+
+// fieldKind is the type of the value held by a Field.
+type fieldKind uint8
+
+// Field kinds.
+const (
+	anyKind fieldKind = iota
+	fieldsKind
+	stringKind
+	intKind
+	int64Kind
+	uint64Kind
+	float64Kind
+	boolKind
+	durationKind
+	timeKind
+	errorKind
+)
+
+// Field is a typed key and value for With. Built with String, Int, Dur
+// and friends, it holds the value without allocating a Fields map or
+// boxing it into an interface{} until the entry is logged.
+type Field struct {
+	Key  string
+	kind fieldKind
+	num  int64
+	str  string
+	ptr  interface{}
+}
+
+// String returns a string field.
+func String(key, value string) Field {
+	return Field{Key: key, kind: stringKind, str: value}
+}
+
+// Int returns an int field.
+func Int(key string, value int) Field {
+	return Field{Key: key, kind: intKind, num: int64(value)}
+}
+
+// Int64 returns an int64 field.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, kind: int64Kind, num: value}
+}
+
+// Uint64 returns a uint64 field.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, kind: uint64Kind, num: int64(value)}
+}
+
+// Float64 returns a float64 field.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, kind: float64Kind, num: int64(math.Float64bits(value))}
+}
+
+// Bool returns a bool field.
+func Bool(key string, value bool) Field {
+	f := Field{Key: key, kind: boolKind}
+	if value {
+		f.num = 1
+	}
+	return f
+}
+
+// Dur returns a time.Duration field.
+func Dur(key string, value time.Duration) Field {
+	return Field{Key: key, kind: durationKind, num: int64(value)}
+}
+
+// Time returns a time.Time field.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, kind: timeKind, ptr: value}
+}
+
+// Err returns an "error" field with the message of `err`, as WithError
+// does without inspecting the error.
+func Err(err error) Field {
+	return Field{Key: "error", kind: errorKind, ptr: err}
+}
+
+// Any returns a field of any value.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, kind: anyKind, ptr: value}
+}
+
+// Value returns the value of the field.
+func (f Field) Value() interface{} {
+	switch f.kind {
+	case stringKind:
+		return f.str
+	case intKind:
+		return int(f.num)
+	case int64Kind:
+		return f.num
+	case uint64Kind:
+		return uint64(f.num)
+	case float64Kind:
+		return math.Float64frombits(uint64(f.num))
+	case boolKind:
+		return f.num == 1
+	case durationKind:
+		return time.Duration(f.num)
+	case timeKind:
+		return f.ptr.(time.Time)
+	case errorKind:
+		if f.ptr == nil {
+			return nil
+		}
+		return f.ptr.(error).Error()
+	default:
+		return f.ptr
+	}
+}
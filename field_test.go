@@ -0,0 +1,145 @@
+package log_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/discard"
+	"github.com/sayden/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_With(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	ts := time.Unix(1, 0).UTC()
+
+	l.With(
+		log.String("file", "sloth.png"),
+		log.Int("size", 1<<20),
+		log.Int64("offset", 5),
+		log.Uint64("crc", 7),
+		log.Float64("ratio", 0.5),
+		log.Bool("public", true),
+		log.Dur("elapsed", time.Second),
+		log.Time("modified", ts),
+		log.Err(errors.New("boom")),
+		log.Any("tags", []string{"a"}),
+	).Info("upload")
+
+	assert.Equal(t, log.Fields{
+		"file":     "sloth.png",
+		"size":     1 << 20,
+		"offset":   int64(5),
+		"crc":      uint64(7),
+		"ratio":    0.5,
+		"public":   true,
+		"elapsed":  time.Second,
+		"modified": ts,
+		"error":    "boom",
+		"tags":     []string{"a"},
+	}, h.Entries[0].GetFields())
+}
+
+func TestLogger_With_order(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.With(log.String("user", "tobi")).
+		WithFields(log.Fields{"user": "loki", "file": "sloth.png"}).
+		With(log.String("file", "tobi.png")).
+		Info("upload")
+
+	assert.Equal(t, log.Fields{"user": "loki", "file": "tobi.png"}, h.Entries[0].GetFields())
+}
+
+func TestTime(t *testing.T) {
+	cases := []time.Time{
+		{},
+		time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2300, 6, 1, 12, 0, 0, 5, time.FixedZone("CET", 3600)),
+	}
+
+	for _, ts := range cases {
+		v := log.Time("t", ts).Value().(time.Time)
+		assert.True(t, ts.Equal(v), "%s != %s", ts, v)
+		assert.Equal(t, ts.Location(), v.Location())
+	}
+}
+
+func BenchmarkLogger_WithFields(b *testing.B) {
+	l := &log.Logger{
+		Handler: discard.New(),
+		Level:   log.LevelInfo,
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		l.WithFields(log.Fields{"file": "sloth.png"}).
+			WithFields(log.Fields{"type": "image/png"}).
+			WithFields(log.Fields{"size": 1 << 20}).
+			Info("upload")
+	}
+}
+
+func BenchmarkLogger_With(b *testing.B) {
+	l := &log.Logger{
+		Handler: discard.New(),
+		Level:   log.LevelInfo,
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		l.With(log.String("file", "sloth.png")).
+			With(log.String("type", "image/png")).
+			With(log.Int("size", 1<<20)).
+			Info("upload")
+	}
+}
+
+func BenchmarkLogger_WithFields_disabled(b *testing.B) {
+	l := &log.Logger{
+		Handler: discard.New(),
+		Level:   log.LevelInfo,
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		l.WithFields(log.Fields{
+			"file": "sloth.png",
+			"type": "image/png",
+			"size": 1 << 20,
+		}).Debug("upload")
+	}
+}
+
+func BenchmarkLogger_With_disabled(b *testing.B) {
+	l := &log.Logger{
+		Handler: discard.New(),
+		Level:   log.LevelInfo,
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		l.With(
+			log.String("file", "sloth.png"),
+			log.String("type", "image/png"),
+			log.Int("size", 1<<20),
+		).Debug("upload")
+	}
+}
@@ -0,0 +1,105 @@
+package log
+
+import "context"
+
+// Flusher is implemented by handlers which buffer entries, such as es
+// and kinesis, to send everything buffered so far.
+type Flusher interface {
+	Flush() error
+}
+
+// Closer is implemented by handlers holding resources such as
+// connections or goroutines, which are released by Close after
+// sending everything buffered so far.
+type Closer interface {
+	Close() error
+}
+
+// Wrapper is implemented by handlers which delegate to other handlers,
+// such as multi and level, so the handler tree can be walked.
+type Wrapper interface {
+	Unwrap() []Handler
+}
+
+// walk calls fn for `h` and then for every handler it wraps, returning
+// the first error while still visiting the rest of the tree.
+func walk(h Handler, fn func(Handler) error) error {
+	if h == nil {
+		return nil
+	}
+
+	err := fn(h)
+
+	if w, ok := h.(Wrapper); ok {
+		for _, c := range w.Unwrap() {
+			if e := walk(c, fn); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+
+	return err
+}
+
+// walkContext walks the handler tree of `h`, giving up when `ctx` is done.
+func walkContext(ctx context.Context, h Handler, fn func(Handler) error) error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- walk(h, fn)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flushHandler flushes `h` when it is a Flusher.
+func flushHandler(h Handler) error {
+	if f, ok := h.(Flusher); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+// closeHandler closes `h` when it is a Closer, or flushes it when it is
+// only a Flusher.
+func closeHandler(h Handler) error {
+	switch v := h.(type) {
+	case Closer:
+		return v.Close()
+	case Flusher:
+		return v.Flush()
+	}
+
+	return nil
+}
+
+// flushHandlers flushes every Flusher in the handler tree of `h`.
+func flushHandlers(ctx context.Context, h Handler) error {
+	return walkContext(ctx, h, flushHandler)
+}
+
+// closeHandlers closes every Closer in the handler tree of `h`, flushing the handlers
+// which are only a Flusher.
+func closeHandlers(ctx context.Context, h Handler) error {
+	return walkContext(ctx, h, closeHandler)
+}
+
+// Flush flushes every Flusher in the handler tree, walking wrappers such
+// as multi and level, until done or `ctx` expires. Call it at the end of
+// a Lambda invocation, for example.
+func (l *Logger) Flush(ctx context.Context) error {
+	return flushHandlers(ctx, l.handler())
+}
+
+// Close closes every Closer and flushes every other Flusher in the
+// handler tree, until done or `ctx` expires. The Logger should not be
+// used afterwards.
+func (l *Logger) Close(ctx context.Context) error {
+	return closeHandlers(ctx, l.handler())
+}
@@ -0,0 +1,119 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/delta"
+	"github.com/sayden/log/handlers/level"
+	"github.com/sayden/log/handlers/memory"
+	"github.com/sayden/log/handlers/multi"
+	"github.com/stretchr/testify/assert"
+)
+
+type closer struct {
+	flusher
+	closed int
+	err    error
+}
+
+func (c *closer) Close() error {
+	c.closed++
+	return c.err
+}
+
+func TestLogger_Flush(t *testing.T) {
+	a := &flusher{}
+	b := &flusher{}
+	c := &closer{}
+
+	l := &log.Logger{
+		Handler: multi.New(a, level.New(multi.New(b, c), log.LevelError), memory.New()),
+		Level:   log.LevelInfo,
+	}
+
+	assert.NoError(t, l.Flush(context.Background()))
+	assert.Equal(t, 1, a.flushed)
+	assert.Equal(t, 1, b.flushed)
+	assert.Equal(t, 1, c.flushed)
+	assert.Equal(t, 0, c.closed)
+}
+
+func TestLogger_Close(t *testing.T) {
+	a := &flusher{}
+	b := &closer{err: errors.New("boom")}
+	c := &closer{}
+
+	l := &log.Logger{
+		Handler: multi.New(a, b, level.New(c, log.LevelError)),
+		Level:   log.LevelInfo,
+	}
+
+	assert.EqualError(t, l.Named("db").Close(context.Background()), "boom")
+	assert.Equal(t, 1, a.flushed)
+	assert.Equal(t, 1, b.closed)
+	assert.Equal(t, 0, b.flushed)
+	assert.Equal(t, 1, c.closed)
+}
+
+func TestLogger_Flush_timeout(t *testing.T) {
+	f := &flusher{block: make(chan struct{})}
+	defer close(f.block)
+
+	l := &log.Logger{
+		Handler: f,
+		Level:   log.LevelInfo,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, l.Flush(ctx))
+}
+
+func TestFlush(t *testing.T) {
+	f := &flusher{}
+	log.SetHandler(f)
+
+	assert.NoError(t, log.Flush(context.Background()))
+	assert.NoError(t, log.Close(context.Background()))
+	assert.Equal(t, 2, f.flushed)
+}
+
+func TestLogger_Fatal_stub(t *testing.T) {
+	c := &closer{}
+
+	l := &log.Logger{
+		Handler:  c,
+		Level:    log.LevelInfo,
+		ExitFunc: func(int) {},
+	}
+
+	l.Fatal("boom")
+	l.Info("still logging")
+
+	assert.Equal(t, 0, c.closed)
+	assert.Equal(t, 1, c.flushed)
+	assert.Equal(t, 2, len(c.Entries))
+}
+
+func TestLogger_Fatal_delta(t *testing.T) {
+	var buf bytes.Buffer
+	h := delta.New(&buf)
+
+	l := &log.Logger{
+		Handler:  h,
+		Level:    log.LevelInfo,
+		ExitFunc: func(int) {},
+	}
+
+	l.Fatal("boom")
+	l.Info("still logging")
+
+	assert.NoError(t, h.Close())
+	assert.Contains(t, buf.String(), "still logging")
+}
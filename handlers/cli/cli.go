@@ -6,7 +6,6 @@ import (
 	"io"
 	"os"
 	"sync"
-	"time"
 
 	"github.com/sayden/log"
 )
@@ -14,9 +13,6 @@ import (
 // Default handler outputting to stderr.
 var Default = New(os.Stderr)
 
-// start time.
-var start = time.Now()
-
 // colors.
 const (
 	none   = 0
@@ -28,21 +24,29 @@ const (
 )
 
 // Colors mapping.
-var Colors = [...]int{
-	log.LevelDebug: gray,
-	log.LevelInfo:  blue,
-	log.LevelWarn:  yellow,
-	log.LevelError: red,
-	log.LevelFatal: red,
+var Colors = map[log.Level]int{
+	log.LevelTrace:    gray,
+	log.LevelDebug:    gray,
+	log.LevelInfo:     blue,
+	log.LevelNotice:   green,
+	log.LevelWarn:     yellow,
+	log.LevelError:    red,
+	log.LevelCritical: red,
+	log.LevelPanic:    red,
+	log.LevelFatal:    red,
 }
 
 // Strings mapping.
-var Strings = [...]string{
-	log.LevelDebug: "•",
-	log.LevelInfo:  "•",
-	log.LevelWarn:  "•",
-	log.LevelError: "⨯",
-	log.LevelFatal: "⨯",
+var Strings = map[log.Level]string{
+	log.LevelTrace:    "•",
+	log.LevelDebug:    "•",
+	log.LevelInfo:     "•",
+	log.LevelNotice:   "•",
+	log.LevelWarn:     "•",
+	log.LevelError:    "⨯",
+	log.LevelCritical: "⨯",
+	log.LevelPanic:    "⨯",
+	log.LevelFatal:    "⨯",
 }
 
 // Handler implementation.
@@ -64,22 +68,58 @@ func New(w io.Writer) *Handler {
 func (h *Handler) HandleLog(e log.Interface) error {
 	color := Colors[e.GetLevel()]
 	level := Strings[e.GetLevel()]
-	names := e.GetFields().Names()
+	names := e.GetFieldNames()
 
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
 	fmt.Fprintf(h.Writer, "\033[%dm%*s\033[0m %-25s", color, h.Padding+1, level, e.GetMessage())
 
+	var chains []log.ErrorChain
+
 	for _, name := range names {
 		if name == "source" {
 			continue
 		}
 
-		fmt.Fprintf(h.Writer, " \033[%dm%s\033[0m=%v", color, name, e.GetFields().Get(name))
+		v := e.GetFields().Get(name)
+
+		if c, ok := v.(log.ErrorChain); ok {
+			chains = append(chains, c)
+			continue
+		}
+
+		fmt.Fprintf(h.Writer, " \033[%dm%s\033[0m=%v", color, name, v)
 	}
 
 	fmt.Fprintln(h.Writer)
 
+	for _, c := range chains {
+		c.WriteIndented(h.Writer, h.Padding+2)
+	}
+
 	return nil
 }
+
+func init() {
+	log.RegisterHandler("cli", func(c log.HandlerConfig) (log.Handler, error) {
+		var opts struct {
+			Padding int `json:"padding"`
+		}
+
+		if err := c.Decode(&opts); err != nil {
+			return nil, err
+		}
+
+		w, err := c.Writer()
+		if err != nil {
+			return nil, err
+		}
+
+		h := New(w)
+		if opts.Padding != 0 {
+			h.Padding = opts.Padding
+		}
+		return c.Closing(h, w), nil
+	})
+}
@@ -0,0 +1,26 @@
+package cli_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/cli"
+)
+
+func TestCollisions(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log.Logger{
+		Handler: cli.New(&buf),
+		Level:   log.LevelInfo,
+	}
+
+	l.WithFields(log.Fields{"level": "debug", "message": "hi"}).Info("hello")
+
+	expected := "\x1b[34m   •\x1b[0m hello                     \x1b[34mlevel\x1b[0m=debug \x1b[34mmessage\x1b[0m=hi\n"
+
+	assert.Equal(t, expected, buf.String())
+}
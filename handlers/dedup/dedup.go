@@ -0,0 +1,196 @@
+// Package dedup implements a handler collapsing repeated entries, so
+// retry loops don't bury everything else.
+package dedup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sayden/log"
+)
+
+// run is a series of entries with the same level, message and fields.
+type run struct {
+	key   string
+	seq   uint64
+	entry log.Interface
+	first time.Time
+	last  time.Time
+	count int
+	timer *time.Timer
+}
+
+// Handler implementation.
+//
+// The first entry of a run of repeats is passed, and the rest are
+// counted. When the run ends a summary entry such as "last message
+// repeated 512 times" is passed, with the fields of the entry and the
+// "dedup.repeated", "dedup.first" and "dedup.last" fields set,
+// namespaced so they don't replace fields of the entry.
+//
+// With a Window, repeats are collapsed even when other entries are
+// logged in between, and a run ends when the Window since its first
+// entry expires. With a zero Window only consecutive repeats are
+// collapsed, and a run ends when a different entry arrives. Flushing
+// the handler ends all runs.
+type Handler struct {
+	Handler log.Handler
+	Window  time.Duration
+
+	mu   sync.Mutex
+	runs map[string]*run
+	seq  uint64
+}
+
+// New handler.
+func New(h log.Handler, window time.Duration) *Handler {
+	return &Handler{
+		Handler: h,
+		Window:  window,
+	}
+}
+
+// Unwrap implements log.Wrapper.
+func (h *Handler) Unwrap() []log.Handler {
+	return []log.Handler{h.Handler}
+}
+
+// HandleLog implements log.Handler.
+func (h *Handler) HandleLog(e log.Interface) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	k := key(e)
+
+	if r, ok := h.runs[k]; ok {
+		r.count++
+		r.last = e.GetTimestamp()
+		return nil
+	}
+
+	var err error
+	if h.Window <= 0 {
+		err = h.endAll()
+	}
+
+	h.seq++
+
+	r := &run{
+		key:   k,
+		seq:   h.seq,
+		entry: e,
+		first: e.GetTimestamp(),
+		last:  e.GetTimestamp(),
+	}
+
+	if h.Window > 0 {
+		r.timer = time.AfterFunc(h.Window, func() { h.expire(r) })
+	}
+
+	if h.runs == nil {
+		h.runs = make(map[string]*run)
+	}
+
+	h.runs[k] = r
+
+	if e := h.Handler.HandleLog(e); e != nil {
+		return e
+	}
+
+	return err
+}
+
+// Flush implements log.Flusher, passing the summaries of the current runs.
+func (h *Handler) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.endAll()
+}
+
+// Close implements log.Closer, passing the summaries of the current runs.
+func (h *Handler) Close() error {
+	return h.Flush()
+}
+
+// expire ends `r` when its window expires.
+func (h *Handler) expire(r *run) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.runs[r.key] != r {
+		return
+	}
+
+	if err := h.end(r); err != nil {
+		log.StdLog().Printf("log/dedup: failed to log summary: %s", err)
+	}
+}
+
+// endAll ends the current runs in the order they started, returning the
+// first error.
+func (h *Handler) endAll() error {
+	runs := make([]*run, 0, len(h.runs))
+	for _, r := range h.runs {
+		runs = append(runs, r)
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].seq < runs[j].seq
+	})
+
+	var err error
+
+	for _, r := range runs {
+		if e := h.end(r); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// end ends `r`, passing its summary when there were repeats.
+func (h *Handler) end(r *run) error {
+	delete(h.runs, r.key)
+
+	if r.timer != nil {
+		r.timer.Stop()
+	}
+
+	if r.count == 0 {
+		return nil
+	}
+
+	fields := log.Fields{}
+	for k, v := range r.entry.GetFields() {
+		fields[k] = v
+	}
+	fields["dedup.repeated"] = r.count
+	fields["dedup.first"] = r.first
+	fields["dedup.last"] = r.last
+
+	return h.Handler.HandleLog(&log.Entry{
+		Fields:    fields,
+		Level:     r.entry.GetLevel(),
+		Timestamp: r.last,
+		Message:   fmt.Sprintf("last message repeated %d times", r.count),
+		Context:   r.entry.GetContext(),
+		Names:     r.entry.GetFieldNames(),
+	})
+}
+
+// key returns what entries repeating `e` have in common.
+func key(e log.Interface) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %q", e.GetLevel(), e.GetMessage())
+
+	fields := e.GetFields()
+	for _, name := range fields.Names() {
+		fmt.Fprintf(&b, " %s=%#v", name, fields[name])
+	}
+
+	return b.String()
+}
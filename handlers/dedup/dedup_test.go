@@ -0,0 +1,158 @@
+package dedup_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/dedup"
+	"github.com/sayden/log/handlers/memory"
+)
+
+func entry(msg string, ts time.Time) *log.Entry {
+	return &log.Entry{
+		Fields:    log.Fields{"file": "sloth.png"},
+		Level:     log.LevelWarn,
+		Message:   msg,
+		Timestamp: ts,
+	}
+}
+
+func Test(t *testing.T) {
+	h := memory.New()
+	d := dedup.New(h, 0)
+
+	start := time.Unix(0, 0)
+
+	for i := 0; i < 512; i++ {
+		assert.NoError(t, d.HandleLog(entry("upload retry", start.Add(time.Duration(i)*time.Second))))
+	}
+	assert.NoError(t, d.HandleLog(entry("upload failed", start.Add(time.Hour))))
+	assert.NoError(t, d.HandleLog(entry("upload retry", start.Add(time.Hour))))
+
+	assert.Equal(t, 4, len(h.Entries))
+	assert.Equal(t, "upload retry", h.Entries[0].GetMessage())
+
+	e := h.Entries[1]
+	assert.Equal(t, "last message repeated 511 times", e.GetMessage())
+	assert.Equal(t, log.LevelWarn, e.GetLevel())
+	assert.Equal(t, log.Fields{
+		"file":           "sloth.png",
+		"dedup.repeated": 511,
+		"dedup.first":    start,
+		"dedup.last":     start.Add(511 * time.Second),
+	}, e.GetFields())
+
+	assert.Equal(t, "upload failed", h.Entries[2].GetMessage())
+	assert.Equal(t, "upload retry", h.Entries[3].GetMessage())
+}
+
+func TestCollisions(t *testing.T) {
+	h := memory.New()
+	d := dedup.New(h, 0)
+
+	for i := 0; i < 3; i++ {
+		e := entry("upload retry", time.Unix(0, 0))
+		e.Fields = log.Fields{"repeated": "yes", "first": "tobi", "last": "loki"}
+		assert.NoError(t, d.HandleLog(e))
+	}
+
+	assert.NoError(t, d.Flush())
+
+	assert.Equal(t, 2, len(h.Entries))
+	assert.Equal(t, log.Fields{
+		"repeated":       "yes",
+		"first":          "tobi",
+		"last":           "loki",
+		"dedup.repeated": 2,
+		"dedup.first":    time.Unix(0, 0),
+		"dedup.last":     time.Unix(0, 0),
+	}, h.Entries[1].GetFields())
+}
+
+func TestFields(t *testing.T) {
+	h := memory.New()
+	d := dedup.New(h, 0)
+
+	a := entry("upload retry", time.Unix(0, 0))
+	b := entry("upload retry", time.Unix(0, 0))
+	b.Fields = log.Fields{"file": "tobi.png"}
+
+	assert.NoError(t, d.HandleLog(a))
+	assert.NoError(t, d.HandleLog(b))
+
+	assert.Equal(t, 2, len(h.Entries))
+}
+
+func TestWindow(t *testing.T) {
+	messages := make(chan string, 2)
+
+	d := dedup.New(log.HandlerFunc(func(e log.Interface) error {
+		messages <- e.GetMessage()
+		return nil
+	}), 10*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, d.HandleLog(entry("upload retry", time.Unix(0, 0))))
+	}
+
+	assert.Equal(t, "upload retry", <-messages)
+
+	select {
+	case msg := <-messages:
+		assert.Equal(t, "last message repeated 2 times", msg)
+	case <-time.After(time.Second):
+		t.Fatal("window did not expire")
+	}
+}
+
+func TestWindow_interleaved(t *testing.T) {
+	h := memory.New()
+	d := dedup.New(h, time.Minute)
+
+	start := time.Unix(0, 0)
+
+	for i := 0; i < 5; i++ {
+		ts := start.Add(time.Duration(i) * time.Second)
+		assert.NoError(t, d.HandleLog(entry("upload retry", ts)))
+
+		tick := entry("tick", ts)
+		tick.Level = log.LevelInfo
+		assert.NoError(t, d.HandleLog(tick))
+	}
+
+	assert.Equal(t, 2, len(h.Entries))
+	assert.Equal(t, "upload retry", h.Entries[0].GetMessage())
+	assert.Equal(t, "tick", h.Entries[1].GetMessage())
+
+	assert.NoError(t, d.Flush())
+
+	assert.Equal(t, 4, len(h.Entries))
+	assert.Equal(t, "last message repeated 4 times", h.Entries[2].GetMessage())
+	assert.Equal(t, log.LevelWarn, h.Entries[2].GetLevel())
+	assert.Equal(t, "last message repeated 4 times", h.Entries[3].GetMessage())
+	assert.Equal(t, log.LevelInfo, h.Entries[3].GetLevel())
+}
+
+func TestFlush(t *testing.T) {
+	h := memory.New()
+	d := dedup.New(h, time.Hour)
+
+	l := &log.Logger{
+		Handler: d,
+		Level:   log.LevelInfo,
+	}
+
+	l.Warn("upload retry")
+	l.Warn("upload retry")
+
+	assert.NoError(t, l.Flush(context.Background()))
+	assert.Equal(t, 2, len(h.Entries))
+	assert.Equal(t, "last message repeated 1 times", h.Entries[1].GetMessage())
+
+	assert.NoError(t, d.Close())
+	assert.Equal(t, 2, len(h.Entries))
+}
@@ -4,9 +4,11 @@
 package delta
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/sayden/log"
@@ -50,42 +52,67 @@ func yellow(s string) string {
 }
 
 // Colors mapping.
-var Colors = [...]colorFunc{
-	log.LevelDebug: gray,
-	log.LevelInfo:  blue,
-	log.LevelWarn:  yellow,
-	log.LevelError: red,
-	log.LevelFatal: red,
+var Colors = map[log.Level]colorFunc{
+	log.LevelTrace:    gray,
+	log.LevelDebug:    gray,
+	log.LevelInfo:     blue,
+	log.LevelNotice:   cyan,
+	log.LevelWarn:     yellow,
+	log.LevelError:    red,
+	log.LevelCritical: red,
+	log.LevelPanic:    red,
+	log.LevelFatal:    red,
 }
 
 // Strings mapping.
-var Strings = [...]string{
-	log.LevelDebug: "DEBU",
-	log.LevelInfo:  "INFO",
-	log.LevelWarn:  "WARN",
-	log.LevelError: "ERRO",
-	log.LevelFatal: "FATA",
+var Strings = map[log.Level]string{
+	log.LevelTrace:    "TRAC",
+	log.LevelDebug:    "DEBU",
+	log.LevelInfo:     "INFO",
+	log.LevelNotice:   "NOTI",
+	log.LevelWarn:     "WARN",
+	log.LevelError:    "ERRO",
+	log.LevelCritical: "CRIT",
+	log.LevelPanic:    "PANI",
+	log.LevelFatal:    "FATA",
 }
 
 // Default handler.
 var Default = New(os.Stderr)
 
+// errClosed is returned for logs handled after Close.
+var errClosed = errors.New("log/delta: handler closed")
+
 // Handler implementation.
 type Handler struct {
+	mu      sync.RWMutex
+	closed  bool
 	entries chan log.Interface
 	start   time.Time
+	last    time.Time
+	seen    time.Time
+	clock   log.Clock
 	spin    *spin.Spinner
 	prev    log.Interface
 	done    chan struct{}
+	stopped chan struct{}
 	w       io.Writer
 }
 
 // New handler.
 func New(w io.Writer) *Handler {
+	return NewWithClock(w, log.SystemClock)
+}
+
+// NewWithClock returns a handler measuring on `c` the time passed since
+// the last entry, while ticking the spinner. Deltas between entries use
+// their timestamps, so the Logger's Clock. A nil `c` is SystemClock.
+func NewWithClock(w io.Writer, c log.Clock) *Handler {
 	h := &Handler{
 		entries: make(chan log.Interface),
 		done:    make(chan struct{}),
-		start:   time.Now(),
+		stopped: make(chan struct{}),
+		clock:   c,
 		spin:    spin.New(),
 		w:       w,
 	}
@@ -95,51 +122,88 @@ func New(w io.Writer) *Handler {
 	return h
 }
 
-// Close the handler.
+// Close implements log.Closer, rendering the last entry and stopping
+// the spinner. The handler must not be used afterwards, and closing it
+// again does nothing.
 func (h *Handler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil
+	}
+
+	h.closed = true
 	h.done <- struct{}{}
-	close(h.done)
-	close(h.entries)
+	<-h.stopped
 	return nil
 }
 
 // loop for rendering.
 func (h *Handler) loop() {
+	defer close(h.stopped)
+
 	ticker := time.NewTicker(100 * time.Millisecond)
 
 	for {
 		select {
 		case e := <-h.entries:
 			if h.prev != nil {
-				h.render(h.prev, true)
+				h.render(h.prev, e.GetTimestamp(), true)
+			} else {
+				h.start = e.GetTimestamp()
 			}
-			h.render(e, false)
+			h.last = e.GetTimestamp()
+			h.seen = h.now()
+			h.render(e, h.last, false)
 			h.prev = e
 		case <-ticker.C:
 			if h.prev != nil {
-				h.render(h.prev, false)
+				h.render(h.prev, h.current(), false)
 			}
 			h.spin.Next()
 		case <-h.done:
 			ticker.Stop()
 			if h.prev != nil {
-				h.render(h.prev, true)
+				h.render(h.prev, h.current(), true)
 			}
 			return
 		}
 	}
 }
 
-func (h *Handler) render(e log.Interface, done bool) {
+// now returns the time on the handler's clock.
+func (h *Handler) now() time.Time {
+	if h.clock == nil {
+		return log.SystemClock.Now()
+	}
+
+	return h.clock.Now()
+}
+
+// current returns the time of the last entry advanced by the time passed
+// since, so it is on the same clock as the entries' timestamps.
+func (h *Handler) current() time.Time {
+	return h.last.Add(h.now().Sub(h.seen))
+}
+
+// render `e` with the delta from the previous entry to `end`, on a new
+// line when `done`.
+func (h *Handler) render(e log.Interface, end time.Time, done bool) {
+	delta := end.Sub(h.start)
+	if delta < 0 {
+		delta = 0
+	}
+
 	color := Colors[e.GetLevel()]
 	level := Strings[e.GetLevel()]
-	names := e.GetFields().Names()
+	names := e.GetFieldNames()
 
 	// delta and spinner
 	if done {
-		fmt.Fprintf(h.w, "\r     %-7s", time.Since(h.start).Round(time.Millisecond))
+		fmt.Fprintf(h.w, "\r     %-7s", delta.Round(time.Millisecond))
 	} else {
-		fmt.Fprintf(h.w, "\r   %s %-7s", h.spin.Current(), time.Since(h.start).Round(time.Millisecond))
+		fmt.Fprintf(h.w, "\r   %s %-7s", h.spin.Current(), delta.Round(time.Millisecond))
 	}
 
 	// message
@@ -159,12 +223,19 @@ func (h *Handler) render(e log.Interface, done bool) {
 	// newline
 	if done {
 		fmt.Fprintf(h.w, "\n")
-		h.start = time.Now()
+		h.start = end
 	}
 }
 
 // HandleLog implements log.Handler.
 func (h *Handler) HandleLog(e log.Interface) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return errClosed
+	}
+
 	h.entries <- e
 	return nil
 }
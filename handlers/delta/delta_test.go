@@ -0,0 +1,97 @@
+package delta_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/delta"
+)
+
+// done returns the lines rendered last before each newline, without the
+// spinner updates.
+func done(s string) []string {
+	var lines []string
+
+	for _, l := range strings.SplitAfter(s, "\n") {
+		if strings.HasSuffix(l, "\n") {
+			lines = append(lines, l[strings.LastIndex(l, "\r"):])
+		}
+	}
+
+	return lines
+}
+
+func TestClock(t *testing.T) {
+	var buf bytes.Buffer
+
+	clock := log.NewFakeClock(time.Unix(100, 0))
+	h := delta.NewWithClock(&buf, log.NewFakeClock(time.Unix(0, 0)))
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+		Clock:   clock,
+	}
+
+	l.Info("upload")
+	clock.Add(2 * time.Second)
+	l.Info("upload complete")
+
+	assert.NoError(t, h.Close())
+
+	color := delta.Colors[log.LevelInfo]
+	level := color(delta.Strings[log.LevelInfo])
+
+	assert.Equal(t, []string{
+		fmt.Sprintf("\r     %-7s %s %s\n", "2s", level, color("upload")),
+		fmt.Sprintf("\r     %-7s %s %s\n", "0s", level, color("upload complete")),
+	}, done(buf.String()))
+}
+
+func TestCollisions(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := delta.New(&buf)
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.WithFields(log.Fields{"level": "debug", "message": "hi"}).Info("hello")
+
+	assert.NoError(t, h.Close())
+
+	color := delta.Colors[log.LevelInfo]
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\r")
+	last := lines[len(lines)-1]
+
+	assert.Contains(t, last, color(delta.Strings[log.LevelInfo])+" "+color("hello"))
+	assert.Contains(t, last, color("level"))
+	assert.Contains(t, last, color("message"))
+	assert.Contains(t, last, "debug")
+}
+
+func TestClose(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := delta.New(&buf)
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.Info("upload")
+
+	assert.NoError(t, h.Close())
+	assert.NoError(t, h.Close())
+	assert.Error(t, h.HandleLog(&log.Entry{Message: "late"}))
+	assert.NotContains(t, buf.String(), "late")
+}
@@ -3,11 +3,12 @@
 package es
 
 import (
+	"errors"
 	"io"
-	stdlog "log"
 	"sync"
 	"time"
 
+	"github.com/tj/go-elastic"
 	"github.com/tj/go-elastic/batch"
 
 	"github.com/sayden/log"
@@ -16,7 +17,6 @@ import (
 // TODO(tj): allow dumping logs to stderr on timeout
 // TODO(tj): allow custom format that does not include .fields etc
 // TODO(tj): allow interval flushes
-// TODO(tj): allow explicit Flush() (for Lambda where you have to flush at the end of function)
 
 // Elasticsearch interface.
 type Elasticsearch interface {
@@ -45,8 +45,10 @@ func (c *Config) defaults() {
 type Handler struct {
 	*Config
 
-	mu    sync.Mutex
-	batch *batch.Batch
+	mu      sync.Mutex
+	idle    *sync.Cond
+	pending int
+	batch   *batch.Batch
 }
 
 // New handler with BufferSize
@@ -73,22 +75,96 @@ func (h *Handler) HandleLog(e log.Interface) error {
 	h.batch.Add(e)
 
 	if h.batch.Size() >= h.BufferSize {
-		go h.flush(h.batch)
+		h.pending++
+		go func(b *batch.Batch) {
+			h.flush(b)
+			h.done()
+		}(h.batch)
 		h.batch = nil
 	}
 
 	return nil
 }
 
-// flush the given `batch` asynchronously.
-func (h *Handler) flush(batch *batch.Batch) {
+// Flush implements log.Flusher, sending the buffered logs and waiting
+// for the batches already being sent.
+func (h *Handler) Flush() error {
+	h.mu.Lock()
+	b := h.batch
+	h.batch = nil
+	h.mu.Unlock()
+
+	var err error
+	if b != nil {
+		err = h.flush(b)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for h.pending > 0 {
+		h.cond().Wait()
+	}
+
+	return err
+}
+
+// done marks a batch sent in the background as flushed.
+func (h *Handler) done() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.pending--
+	if h.pending == 0 {
+		h.cond().Broadcast()
+	}
+}
+
+// cond returns the condition signaled when no batch is being sent. It
+// must be called with the mutex held.
+func (h *Handler) cond() *sync.Cond {
+	if h.idle == nil {
+		h.idle = sync.NewCond(&h.mu)
+	}
+
+	return h.idle
+}
+
+// flush the given `batch`.
+func (h *Handler) flush(batch *batch.Batch) error {
 	size := batch.Size()
 	start := time.Now()
-	stdlog.Printf("log/elastic: flushing %d logs", size)
+	log.StdLog().Printf("log/elastic: flushing %d logs", size)
 
 	if err := batch.Flush(); err != nil {
-		stdlog.Printf("log/elastic: failed to flush %d logs: %s", size, err)
+		log.StdLog().Printf("log/elastic: failed to flush %d logs: %s", size, err)
+		return err
 	}
 
-	stdlog.Printf("log/elastic: flushed %d logs in %s", size, time.Since(start))
+	log.StdLog().Printf("log/elastic: flushed %d logs in %s", size, time.Since(start))
+	return nil
+}
+
+func init() {
+	log.RegisterHandler("es", func(c log.HandlerConfig) (log.Handler, error) {
+		var opts struct {
+			URL        string `json:"url"`
+			BufferSize int    `json:"buffer_size"`
+			Format     string `json:"format"`
+		}
+
+		if err := c.Decode(&opts); err != nil {
+			return nil, err
+		}
+
+		if opts.URL == "" {
+			return nil, errors.New("missing url")
+		}
+
+		return New(&Config{
+			BufferSize: opts.BufferSize,
+			Format:     opts.Format,
+			Client:     elastic.New(opts.URL),
+		}), nil
+	})
 }
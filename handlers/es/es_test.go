@@ -0,0 +1,88 @@
+package es_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/es"
+)
+
+type client struct {
+	mu    sync.Mutex
+	lines int
+	body  []byte
+}
+
+func (c *client) Bulk(r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines += bytes.Count(b, []byte("\n"))
+	c.body = b
+	return nil
+}
+
+func TestFlush(t *testing.T) {
+	c := &client{}
+
+	l := &log.Logger{
+		Handler: es.New(&es.Config{Client: c, BufferSize: 3}),
+		Level:   log.LevelInfo,
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				l.Info("upload")
+				assert.NoError(t, l.Handler.(log.Flusher).Flush())
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.NoError(t, l.Handler.(log.Flusher).Flush())
+
+	// the bulk body has an action and a document line per entry.
+	assert.Equal(t, 200, c.lines)
+}
+
+func TestCollisions(t *testing.T) {
+	c := &client{}
+	h := es.New(&es.Config{Client: c})
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.WithFields(log.Fields{"level": "debug", "message": "hi"}).Info("hello")
+	assert.NoError(t, h.Flush())
+
+	lines := bytes.Split(bytes.TrimSpace(c.body), []byte("\n"))
+	assert.Equal(t, 2, len(lines))
+
+	var doc struct {
+		Level   string     `json:"level"`
+		Message string     `json:"message"`
+		Fields  log.Fields `json:"fields"`
+	}
+
+	assert.NoError(t, json.Unmarshal(lines[1], &doc))
+	assert.Equal(t, "info", doc.Level)
+	assert.Equal(t, "hello", doc.Message)
+	assert.Equal(t, log.Fields{"level": "debug", "message": "hi"}, doc.Fields)
+}
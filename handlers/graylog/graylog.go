@@ -6,8 +6,14 @@ import (
 	"github.com/aphistic/golf"
 )
 
+// Reserved keys, which GELF does not allow as additional fields.
+var Reserved = []string{"id"}
+
 // Handler implementation.
 type Handler struct {
+	// Collisions is the naming of the fields colliding with Reserved.
+	Collisions log.CollisionPolicy
+
 	logger *golf.Logger
 	client *golf.Client
 }
@@ -39,23 +45,72 @@ func New(url string) (*Handler, error) {
 
 // HandleLog implements log.Handler.
 func (h *Handler) HandleLog(e log.Interface) error {
+	fields, err := h.fields(e)
+	if err != nil {
+		return err
+	}
+
 	switch e.GetLevel() {
-	case log.LevelDebug:
-		return h.logger.Dbgm(e.GetFields(), e.GetMessage())
+	case log.LevelTrace, log.LevelDebug:
+		return h.logger.Dbgm(fields, e.GetMessage())
 	case log.LevelInfo:
-		return h.logger.Infom(e.GetFields(), e.GetMessage())
+		return h.logger.Infom(fields, e.GetMessage())
+	case log.LevelNotice:
+		return h.logger.Noticem(fields, e.GetMessage())
 	case log.LevelWarn:
-		return h.logger.Warnm(e.GetFields(), e.GetMessage())
+		return h.logger.Warnm(fields, e.GetMessage())
 	case log.LevelError:
-		return h.logger.Errm(e.GetFields(), e.GetMessage())
+		return h.logger.Errm(fields, e.GetMessage())
+	case log.LevelCritical:
+		return h.logger.Critm(fields, e.GetMessage())
+	case log.LevelPanic:
+		return h.logger.Alertm(fields, e.GetMessage())
 	case log.LevelFatal:
-		return h.logger.Critm(e.GetFields(), e.GetMessage())
+		return h.logger.Emergm(fields, e.GetMessage())
 	}
 
 	return nil
 }
 
-// Closes connection to server, flushing message queue.
+// fields returns the fields of `e`, renaming the ones colliding with Reserved.
+func (h *Handler) fields(e log.Interface) (log.Fields, error) {
+	fields := e.GetFields()
+	renamed := make(log.Fields, len(fields))
+
+	for name, v := range fields {
+		k, err := h.Collisions.Rename(name, fields, Reserved...)
+		if err != nil {
+			return nil, err
+		}
+		renamed[k] = v
+	}
+
+	return renamed, nil
+}
+
+// Close implements log.Closer, closing the connection to the server
+// and flushing the message queue.
 func (h *Handler) Close() error {
 	return h.client.Close()
 }
+
+func init() {
+	log.RegisterHandler("graylog", func(c log.HandlerConfig) (log.Handler, error) {
+		var opts struct {
+			URL        string              `json:"url"`
+			Collisions log.CollisionPolicy `json:"collisions"`
+		}
+
+		if err := c.Decode(&opts); err != nil {
+			return nil, err
+		}
+
+		h, err := New(opts.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		h.Collisions = opts.Collisions
+		return h, nil
+	})
+}
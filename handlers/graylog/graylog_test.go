@@ -0,0 +1,51 @@
+package graylog_test
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/graylog"
+)
+
+func TestCollisions(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	h, err := graylog.New("udp://" + conn.LocalAddr().String() + "?compress=none")
+	assert.NoError(t, err)
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.WithFields(log.Fields{
+		"id":      "123",
+		"level":   "debug",
+		"message": "hi",
+	}).Info("hello")
+
+	assert.NoError(t, h.Close())
+
+	b := make([]byte, 8192)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(b)
+	assert.NoError(t, err)
+
+	// skip the header of the single GELF chunk
+	var msg map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b[12:n], &msg))
+
+	assert.Equal(t, "hello", msg["short_message"])
+	assert.Equal(t, float64(6), msg["level"])
+	assert.Equal(t, "123", msg["_fields.id"])
+	assert.Equal(t, "debug", msg["_level"])
+	assert.Equal(t, "hi", msg["_message"])
+	assert.NotContains(t, msg, "_id")
+}
@@ -32,3 +32,18 @@ func (h *Handler) HandleLog(e log.Interface) error {
 	defer h.mu.Unlock()
 	return h.Encoder.Encode(e)
 }
+
+func init() {
+	log.RegisterHandler("json", func(c log.HandlerConfig) (log.Handler, error) {
+		if err := c.Decode(&struct{}{}); err != nil {
+			return nil, err
+		}
+
+		w, err := c.Writer()
+		if err != nil {
+			return nil, err
+		}
+
+		return c.Closing(New(w), w), nil
+	})
+}
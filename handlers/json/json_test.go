@@ -32,3 +32,19 @@ func Test(t *testing.T) {
 
 	assert.Equal(t, expected, buf.String())
 }
+
+func TestReservedKeys(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log.Logger{
+		Handler: json.New(&buf),
+		Level:   log.LevelInfo,
+	}
+
+	l.WithField("level", "debug").WithField("message", "hi").Info("hello")
+
+	expected := `{"fields":{"level":"debug","message":"hi"},"level":"info","timestamp":"1970-01-01T00:00:00Z","message":"hello"}
+`
+
+	assert.Equal(t, expected, buf.String())
+}
@@ -3,6 +3,8 @@ package kinesis
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"sync"
 
 	"github.com/sayden/log"
 	"github.com/aws/aws-sdk-go/aws"
@@ -15,10 +17,16 @@ import (
 // Handler implementation.
 type Handler struct {
 	appName  string
+	mu       sync.RWMutex
+	config   k.Config
 	producer *k.Producer
+	closed   bool
 	gen      *fastuuid.Generator
 }
 
+// errClosed is returned for logs handled after Close.
+var errClosed = errors.New("log/kinesis: handler closed")
+
 // New handler sending logs to Kinesis. To configure producer options or pass your
 // own AWS Kinesis client use NewConfig instead.
 func New(stream string) *Handler {
@@ -34,11 +42,49 @@ func NewConfig(config k.Config) *Handler {
 	producer := k.New(config)
 	producer.Start()
 	return &Handler{
+		config:   config,
 		producer: producer,
 		gen:      fastuuid.MustNewGenerator(),
 	}
 }
 
+// Flush implements log.Flusher. The producer can only be drained by
+// stopping it, so a new one takes its place for the following logs.
+// Flushing after Close does nothing.
+func (h *Handler) Flush() error {
+	h.mu.Lock()
+
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+
+	producer := k.New(h.config)
+	producer.Start()
+
+	old := h.producer
+	h.producer = producer
+	h.mu.Unlock()
+
+	old.Stop()
+	return nil
+}
+
+// Close implements log.Closer, draining the producer. The handler must
+// not be used afterwards, and closing it again does nothing.
+func (h *Handler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil
+	}
+
+	h.closed = true
+	h.producer.Stop()
+	return nil
+}
+
 // HandleLog implements log.Handler.
 func (h *Handler) HandleLog(e log.Interface) error {
 	b, err := json.Marshal(e)
@@ -48,5 +94,31 @@ func (h *Handler) HandleLog(e log.Interface) error {
 
 	uuid := h.gen.Next()
 	key := base64.StdEncoding.EncodeToString(uuid[:])
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return errClosed
+	}
+
 	return h.producer.Put(b, key)
 }
+
+func init() {
+	log.RegisterHandler("kinesis", func(c log.HandlerConfig) (log.Handler, error) {
+		var opts struct {
+			Stream string `json:"stream"`
+		}
+
+		if err := c.Decode(&opts); err != nil {
+			return nil, err
+		}
+
+		if opts.Stream == "" {
+			return nil, errors.New("missing stream")
+		}
+
+		return New(opts.Stream), nil
+	})
+}
@@ -0,0 +1,98 @@
+package kinesis_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awskinesis "github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
+	"github.com/stretchr/testify/assert"
+	k "github.com/tj/go-kinesis"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/kinesis"
+)
+
+type client struct {
+	kinesisiface.KinesisAPI
+	records chan []byte
+}
+
+func (c *client) PutRecords(in *awskinesis.PutRecordsInput) (*awskinesis.PutRecordsOutput, error) {
+	if c.records != nil {
+		for _, r := range in.Records {
+			c.records <- r.Data
+		}
+	}
+
+	return &awskinesis.PutRecordsOutput{FailedRecordCount: aws.Int64(0)}, nil
+}
+
+func TestClose(t *testing.T) {
+	c := &client{}
+	h := kinesis.NewConfig(k.Config{StreamName: "logs", Client: c})
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.Info("upload")
+	assert.NoError(t, h.Flush())
+	l.Info("upload complete")
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		assert.NoError(t, h.Close())
+		assert.NoError(t, h.Close())
+		assert.NoError(t, h.Flush())
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("flushing after close blocked")
+	}
+
+	assert.Error(t, h.HandleLog(&log.Entry{Message: "late"}))
+}
+
+func TestCollisions(t *testing.T) {
+	c := &client{records: make(chan []byte, 1)}
+	h := kinesis.NewConfig(k.Config{
+		StreamName:    "logs",
+		Client:        c,
+		FlushInterval: 10 * time.Millisecond,
+	})
+	defer h.Close()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.WithFields(log.Fields{"level": "debug", "message": "hi"}).Info("hello")
+
+	var b []byte
+
+	select {
+	case b = <-c.records:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no record sent")
+	}
+
+	var doc struct {
+		Level   string     `json:"level"`
+		Message string     `json:"message"`
+		Fields  log.Fields `json:"fields"`
+	}
+
+	assert.NoError(t, json.Unmarshal(b, &doc))
+	assert.Equal(t, "info", doc.Level)
+	assert.Equal(t, "hello", doc.Message)
+	assert.Equal(t, log.Fields{"level": "debug", "message": "hi"}, doc.Fields)
+}
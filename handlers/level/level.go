@@ -1,7 +1,11 @@
 // Package level implements a level filter handler.
 package level
 
-import "github.com/sayden/log"
+import (
+	"fmt"
+
+	"github.com/sayden/log"
+)
 
 // Handler implementation.
 type Handler struct {
@@ -17,6 +21,11 @@ func New(h log.Handler, level log.Level) *Handler {
 	}
 }
 
+// Unwrap implements log.Wrapper.
+func (h *Handler) Unwrap() []log.Handler {
+	return []log.Handler{h.Handler}
+}
+
 // HandleLog implements log.Handler.
 func (h *Handler) HandleLog(e log.Interface) error {
 	if e.GetLevel() < h.Level {
@@ -25,3 +34,23 @@ func (h *Handler) HandleLog(e log.Interface) error {
 
 	return h.Handler.HandleLog(e)
 }
+
+func init() {
+	log.RegisterHandler("level", func(c log.HandlerConfig) (log.Handler, error) {
+		level, err := log.ParseLevel(c.Level)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(c.Handlers) != 1 {
+			return nil, fmt.Errorf("expected one handler, got %d", len(c.Handlers))
+		}
+
+		handlers, err := c.Children()
+		if err != nil {
+			return nil, err
+		}
+
+		return New(handlers[0], level), nil
+	})
+}
@@ -13,8 +13,14 @@ import (
 // Default handler outputting to stderr.
 var Default = New(os.Stderr)
 
+// Reserved keys, written for every entry.
+var Reserved = []string{"timestamp", "level", "message"}
+
 // Handler implementation.
 type Handler struct {
+	// Collisions is the naming of the fields colliding with Reserved.
+	Collisions log.CollisionPolicy
+
 	mu  sync.Mutex
 	enc *logfmt.Encoder
 }
@@ -28,7 +34,17 @@ func New(w io.Writer) *Handler {
 
 // HandleLog implements log.Handler.
 func (h *Handler) HandleLog(e log.Interface) error {
-	names := e.GetFields().Names()
+	names := e.GetFieldNames()
+	fields := e.GetFields()
+	keys := make([]string, len(names))
+
+	for i, name := range names {
+		k, err := h.Collisions.Rename(name, fields, Reserved...)
+		if err != nil {
+			return err
+		}
+		keys[i] = k
+	}
 
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -37,11 +53,32 @@ func (h *Handler) HandleLog(e log.Interface) error {
 	h.enc.EncodeKeyval("level", e.GetLevel().String())
 	h.enc.EncodeKeyval("message", e.GetMessage())
 
-	for _, name := range names {
-		h.enc.EncodeKeyval(name, e.GetFields().Get(name))
+	for i, name := range names {
+		h.enc.EncodeKeyval(keys[i], fields[name])
 	}
 
 	h.enc.EndRecord()
 
 	return nil
 }
+
+func init() {
+	log.RegisterHandler("logfmt", func(c log.HandlerConfig) (log.Handler, error) {
+		var opts struct {
+			Collisions log.CollisionPolicy `json:"collisions"`
+		}
+
+		if err := c.Decode(&opts); err != nil {
+			return nil, err
+		}
+
+		w, err := c.Writer()
+		if err != nil {
+			return nil, err
+		}
+
+		h := New(w)
+		h.Collisions = opts.Collisions
+		return c.Closing(h, w), nil
+	})
+}
@@ -42,3 +42,45 @@ func Benchmark(b *testing.B) {
 		ctx.Info("hello")
 	}
 }
+
+func TestCollisions(t *testing.T) {
+	cases := []struct {
+		policy   log.CollisionPolicy
+		expected string
+	}{
+		{log.CollisionPrefix, "timestamp=1970-01-01T00:00:00Z level=info message=hello fields.level=debug fields.message=hi user=tj\n"},
+		{log.CollisionSuffix, "timestamp=1970-01-01T00:00:00Z level=info message=hello level_=debug message_=hi user=tj\n"},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+
+		h := logfmt.New(&buf)
+		h.Collisions = c.policy
+
+		l := &log.Logger{
+			Handler: h,
+			Level:   log.LevelInfo,
+		}
+
+		l.WithFields(log.Fields{"level": "debug", "message": "hi", "user": "tj"}).Info("hello")
+
+		assert.Equal(t, c.expected, buf.String())
+	}
+}
+
+func TestCollisions_error(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := logfmt.New(&buf)
+	h.Collisions = log.CollisionError
+
+	err := h.HandleLog(&log.Entry{
+		Fields:  log.Fields{"timestamp": "now"},
+		Level:   log.LevelInfo,
+		Message: "hello",
+	})
+
+	assert.ErrorIs(t, err, log.ErrReservedKey)
+	assert.Empty(t, buf.String())
+}
@@ -17,6 +17,11 @@ func New(h ...log.Handler) *Handler {
 	}
 }
 
+// Unwrap implements log.Wrapper.
+func (h *Handler) Unwrap() []log.Handler {
+	return h.Handlers
+}
+
 // HandleLog implements log.Handler.
 func (h *Handler) HandleLog(e log.Interface) error {
 	for _, handler := range h.Handlers {
@@ -30,3 +35,14 @@ func (h *Handler) HandleLog(e log.Interface) error {
 
 	return nil
 }
+
+func init() {
+	log.RegisterHandler("multi", func(c log.HandlerConfig) (log.Handler, error) {
+		handlers, err := c.Children()
+		if err != nil {
+			return nil, err
+		}
+
+		return New(handlers...), nil
+	})
+}
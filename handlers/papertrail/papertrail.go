@@ -17,6 +17,19 @@ import (
 // TODO: syslog portion is ad-hoc for my serverless use-case,
 // I don't really need hostnames etc, but this should be improved
 
+// Severities mapping.
+var Severities = map[log.Level]syslog.Priority{
+	log.LevelTrace:    syslog.LOG_DEBUG,
+	log.LevelDebug:    syslog.LOG_DEBUG,
+	log.LevelInfo:     syslog.LOG_INFO,
+	log.LevelNotice:   syslog.LOG_NOTICE,
+	log.LevelWarn:     syslog.LOG_WARNING,
+	log.LevelError:    syslog.LOG_ERR,
+	log.LevelCritical: syslog.LOG_CRIT,
+	log.LevelPanic:    syslog.LOG_ALERT,
+	log.LevelFatal:    syslog.LOG_EMERG,
+}
+
 // Config for Papertrail.
 type Config struct {
 	// Papertrail settings.
@@ -26,8 +39,14 @@ type Config struct {
 	// Application settings
 	Hostname string // Hostname value
 	Tag      string // Tag value
+
+	// Collisions is the naming of the fields colliding with Reserved.
+	Collisions log.CollisionPolicy
 }
 
+// Reserved keys, written for every entry.
+var Reserved = []string{"level", "message"}
+
 // Handler implementation.
 type Handler struct {
 	*Config
@@ -38,38 +57,89 @@ type Handler struct {
 
 // New handler.
 func New(config *Config) *Handler {
-	conn, err := net.Dial("udp", fmt.Sprintf("%s.papertrailapp.com:%d", config.Host, config.Port))
+	h, err := dial(config)
 	if err != nil {
 		panic(err)
 	}
 
+	return h
+}
+
+// dial returns a handler connected to Papertrail.
+func dial(config *Config) (*Handler, error) {
+	conn, err := net.Dial("udp", fmt.Sprintf("%s.papertrailapp.com:%d", config.Host, config.Port))
+	if err != nil {
+		return nil, err
+	}
+
 	return &Handler{
 		Config: config,
 		conn:   conn,
-	}
+	}, nil
+}
+
+func init() {
+	log.RegisterHandler("papertrail", func(c log.HandlerConfig) (log.Handler, error) {
+		var opts struct {
+			Host       string              `json:"host"`
+			Port       int                 `json:"port"`
+			Hostname   string              `json:"hostname"`
+			Tag        string              `json:"tag"`
+			Collisions log.CollisionPolicy `json:"collisions"`
+		}
+
+		if err := c.Decode(&opts); err != nil {
+			return nil, err
+		}
+
+		return dial(&Config{
+			Host:       opts.Host,
+			Port:       opts.Port,
+			Hostname:   opts.Hostname,
+			Tag:        opts.Tag,
+			Collisions: opts.Collisions,
+		})
+	})
 }
 
 // HandleLog implements log.Handler.
 func (h *Handler) HandleLog(e log.Interface) error {
 	ts := time.Now().Format(time.Stamp)
 
+	body, err := encode(e, h.Collisions)
+	if err != nil {
+		return err
+	}
+
+	msg := []byte(fmt.Sprintf("<%d>%s %s %s[%d]: %s\n", syslog.LOG_KERN|Severities[e.GetLevel()], ts, h.Hostname, h.Tag, os.Getpid(), body))
+
+	h.mu.Lock()
+	_, err = h.conn.Write(msg)
+	h.mu.Unlock()
+
+	return err
+}
+
+// encode returns the logfmt encoding of `e`, naming the fields colliding
+// with Reserved with `p`.
+func encode(e log.Interface, p log.CollisionPolicy) (string, error) {
 	var buf bytes.Buffer
 
 	enc := logfmt.NewEncoder(&buf)
 	enc.EncodeKeyval("level", e.GetLevel().String())
 	enc.EncodeKeyval("message", e.GetMessage())
 
-	for k, v := range e.GetFields() {
-		enc.EncodeKeyval(k, v)
+	fields := e.GetFields()
+	for _, name := range e.GetFieldNames() {
+		k, err := p.Rename(name, fields, Reserved...)
+		if err != nil {
+			return "", err
+		}
+
+		enc.EncodeKeyval(k, fields[name])
 	}
 
 	enc.EndRecord()
 
-	msg := []byte(fmt.Sprintf("<%d>%s %s %s[%d]: %s\n", syslog.LOG_KERN, ts, h.Hostname, h.Tag, os.Getpid(), buf.String()))
-
-	h.mu.Lock()
-	_, err := h.conn.Write(msg)
-	h.mu.Unlock()
-
-	return err
+	return buf.String(), nil
 }
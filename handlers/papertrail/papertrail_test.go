@@ -0,0 +1,28 @@
+package papertrail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+)
+
+func TestEncode(t *testing.T) {
+	e := &log.Entry{
+		Fields:  log.Fields{"level": "debug", "user": "tj", "timestamp": "now"},
+		Level:   log.LevelInfo,
+		Message: "hello",
+	}
+
+	s, err := encode(e, log.CollisionPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, "level=info message=hello fields.level=debug timestamp=now user=tj\n", s)
+
+	s, err = encode(e, log.CollisionSuffix)
+	assert.NoError(t, err)
+	assert.Equal(t, "level=info message=hello level_=debug timestamp=now user=tj\n", s)
+
+	_, err = encode(e, log.CollisionError)
+	assert.ErrorIs(t, err, log.ErrReservedKey)
+}
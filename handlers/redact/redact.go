@@ -0,0 +1,36 @@
+// Package redact implements a handler redacting sensitive fields before
+// passing entries to another handler.
+package redact
+
+import "github.com/sayden/log"
+
+// Handler implementation.
+type Handler struct {
+	Redactor *log.Redactor
+	Handler  log.Handler
+}
+
+// New handler redacting entries with `r` before passing them to `h`.
+func New(h log.Handler, r *log.Redactor) *Handler {
+	return &Handler{
+		Redactor: r,
+		Handler:  h,
+	}
+}
+
+// Unwrap implements log.Wrapper.
+func (h *Handler) Unwrap() []log.Handler {
+	return []log.Handler{h.Handler}
+}
+
+// HandleLog implements log.Handler.
+func (h *Handler) HandleLog(e log.Interface) error {
+	return h.Handler.HandleLog(&log.Entry{
+		Fields:    h.Redactor.Redact(e.GetFields()),
+		Level:     e.GetLevel(),
+		Timestamp: e.GetTimestamp(),
+		Message:   e.GetMessage(),
+		Context:   e.GetContext(),
+		Names:     e.GetFieldNames(),
+	})
+}
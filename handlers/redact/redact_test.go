@@ -0,0 +1,29 @@
+package redact_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/memory"
+	"github.com/sayden/log/handlers/redact"
+)
+
+func Test(t *testing.T) {
+	h := memory.New()
+
+	ctx := log.Logger{
+		Handler: redact.New(h, &log.Redactor{Keys: []string{"*_token"}}),
+		Level:   log.LevelInfo,
+	}
+
+	ctx.WithField("user", "tobi").WithField("api_token", "abc").Info("login")
+
+	assert.Equal(t, 1, len(h.Entries))
+
+	e := h.Entries[0]
+	assert.Equal(t, "login", e.GetMessage())
+	assert.Equal(t, log.LevelInfo, e.GetLevel())
+	assert.Equal(t, log.Fields{"user": "tobi", "api_token": log.DefaultMask}, e.GetFields())
+}
@@ -0,0 +1,171 @@
+// Package sample implements a handler sampling repeated entries, so hot
+// paths don't flood the handlers it wraps.
+package sample
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sayden/log"
+)
+
+// counter tracks the entries of a level and message in an interval.
+type counter struct {
+	start   time.Time
+	n       int
+	dropped int
+}
+
+// key identifies the entries sampled together.
+type key struct {
+	level   log.Level
+	message string
+}
+
+// Handler implementation.
+//
+// For each level and message, the First entries of every Interval are
+// passed, and then one of every Thereafter, or none when it is zero. The
+// first entry passed after some were dropped has the "sampled" field set
+// to how many were. With a zero Interval the counts are never reset, so
+// there is a single interval for the lifetime of the handler.
+type Handler struct {
+	Handler    log.Handler
+	First      int
+	Thereafter int
+	Interval   time.Duration
+
+	// Telemetry, if set, is incremented by one on the Counter for every
+	// dropped entry, DefaultCounter when empty. The counter must be
+	// registered with the Telemetry. Failing to increment it does not
+	// fail logging, and is reported once.
+	Telemetry log.Telemetry
+	Counter   string
+
+	report   sync.Once
+	mu       sync.Mutex
+	counters map[key]*counter
+	pruned   time.Time
+}
+
+// DefaultCounter is the Counter used when none is set.
+const DefaultCounter = "log_sampled"
+
+// New handler.
+func New(h log.Handler, first, thereafter int, interval time.Duration) *Handler {
+	return &Handler{
+		Handler:    h,
+		First:      first,
+		Thereafter: thereafter,
+		Interval:   interval,
+		Counter:    DefaultCounter,
+	}
+}
+
+// Unwrap implements log.Wrapper.
+func (h *Handler) Unwrap() []log.Handler {
+	return []log.Handler{h.Handler}
+}
+
+// HandleLog implements log.Handler.
+func (h *Handler) HandleLog(e log.Interface) error {
+	dropped, ok := h.sample(e)
+
+	if !ok {
+		h.inc()
+		return nil
+	}
+
+	if dropped == 0 {
+		return h.Handler.HandleLog(e)
+	}
+
+	fields := log.Fields{}
+	for k, v := range e.GetFields() {
+		fields[k] = v
+	}
+	fields["sampled"] = dropped
+
+	return h.Handler.HandleLog(&log.Entry{
+		Fields:    fields,
+		Level:     e.GetLevel(),
+		Timestamp: e.GetTimestamp(),
+		Message:   e.GetMessage(),
+		Context:   e.GetContext(),
+		Names:     e.GetFieldNames(),
+	})
+}
+
+// inc increments the Counter of dropped entries, reporting the first
+// error only since it would repeat for every entry dropped.
+func (h *Handler) inc() {
+	if h.Telemetry == nil {
+		return
+	}
+
+	name := h.Counter
+	if name == "" {
+		name = DefaultCounter
+	}
+
+	if err := h.Telemetry.Inc(name, 1); err != nil {
+		h.report.Do(func() {
+			log.StdLog().Printf("log/sample: failed to increment %s: %s", name, err)
+		})
+	}
+}
+
+// sample returns whether `e` is passed, and how many entries were
+// dropped since the last one passed.
+func (h *Handler) sample(e log.Interface) (int, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.counters == nil {
+		h.counters = make(map[key]*counter)
+	}
+
+	now := e.GetTimestamp()
+	h.prune(now)
+
+	k := key{level: e.GetLevel(), message: e.GetMessage()}
+
+	c, ok := h.counters[k]
+	if !ok {
+		c = &counter{start: now}
+		h.counters[k] = c
+	}
+
+	if h.Interval > 0 && now.Sub(c.start) >= h.Interval {
+		c.start = now
+		c.n = 0
+	}
+
+	c.n++
+
+	if c.n <= h.First || (h.Thereafter > 0 && (c.n-h.First)%h.Thereafter == 0) {
+		dropped := c.dropped
+		c.dropped = 0
+		return dropped, true
+	}
+
+	c.dropped++
+	return 0, false
+}
+
+// prune removes the counters of finished intervals without dropped
+// entries, once per interval, so the map doesn't grow with every message.
+// There is nothing to prune with a zero Interval, as counts are kept.
+func (h *Handler) prune(now time.Time) {
+	if h.Interval <= 0 || now.Sub(h.pruned) < h.Interval {
+		return
+	}
+
+	for k, c := range h.counters {
+		if now.Sub(c.start) >= h.Interval && c.dropped == 0 {
+			delete(h.counters, k)
+		}
+	}
+
+	h.pruned = now
+}
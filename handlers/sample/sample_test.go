@@ -0,0 +1,132 @@
+package sample_test
+
+import (
+	"bytes"
+	"errors"
+	stdlog "log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/memory"
+	"github.com/sayden/log/handlers/sample"
+)
+
+func entry(level log.Level, msg string, ts time.Time) *log.Entry {
+	return &log.Entry{
+		Fields:    log.Fields{"user": "tobi"},
+		Level:     level,
+		Message:   msg,
+		Timestamp: ts,
+	}
+}
+
+func Test(t *testing.T) {
+	h := memory.New()
+	s := sample.New(h, 2, 3, time.Second)
+
+	start := time.Unix(0, 0)
+
+	for i := 0; i < 7; i++ {
+		assert.NoError(t, s.HandleLog(entry(log.LevelInfo, "upload", start)))
+	}
+	assert.NoError(t, s.HandleLog(entry(log.LevelWarn, "upload", start)))
+
+	assert.Equal(t, 4, len(h.Entries))
+	assert.Equal(t, log.Fields{"user": "tobi"}, h.Entries[0].GetFields())
+	assert.Equal(t, log.Fields{"user": "tobi"}, h.Entries[1].GetFields())
+	assert.Equal(t, log.Fields{"user": "tobi", "sampled": 2}, h.Entries[2].GetFields())
+	assert.Equal(t, log.LevelWarn, h.Entries[3].GetLevel())
+
+	assert.NoError(t, s.HandleLog(entry(log.LevelInfo, "upload", start.Add(time.Second))))
+
+	assert.Equal(t, 5, len(h.Entries))
+	assert.Equal(t, log.Fields{"user": "tobi", "sampled": 2}, h.Entries[4].GetFields())
+}
+
+func TestHandler_literal(t *testing.T) {
+	h := memory.New()
+	c := &counter{counts: map[string]float64{}}
+
+	s := &sample.Handler{
+		Handler:    h,
+		First:      1,
+		Thereafter: 2,
+		Interval:   time.Second,
+		Telemetry:  c,
+	}
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, s.HandleLog(entry(log.LevelInfo, "upload", time.Unix(0, 0))))
+	}
+
+	assert.Equal(t, 3, len(h.Entries))
+	assert.Equal(t, map[string]float64{sample.DefaultCounter: 2}, c.counts)
+}
+
+func TestHandler_zeroInterval(t *testing.T) {
+	h := memory.New()
+	s := sample.New(h, 2, 0, 0)
+
+	start := time.Unix(0, 0)
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, s.HandleLog(entry(log.LevelInfo, "upload", start.Add(time.Duration(i)*time.Hour))))
+	}
+
+	assert.Equal(t, 2, len(h.Entries))
+}
+
+type counter struct {
+	counts map[string]float64
+	err    error
+}
+
+func (c *counter) Inc(name string, value float64, tags ...string) error {
+	c.counts[name] += value
+	return c.err
+}
+
+func (c *counter) SetPrometheusInc(string, *prometheus.CounterVec) {}
+
+func (c *counter) SetNamespace(string) {}
+
+func TestTelemetry(t *testing.T) {
+	h := memory.New()
+	c := &counter{counts: map[string]float64{}}
+
+	s := sample.New(h, 1, 0, time.Minute)
+	s.Telemetry = c
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, s.HandleLog(entry(log.LevelInfo, "upload", time.Unix(0, 0))))
+	}
+
+	assert.Equal(t, 1, len(h.Entries))
+	assert.Equal(t, map[string]float64{"log_sampled": 4}, c.counts)
+}
+
+func TestTelemetry_error(t *testing.T) {
+	var buf bytes.Buffer
+	w := stdlog.Writer()
+	stdlog.SetOutput(&buf)
+	defer stdlog.SetOutput(w)
+
+	h := memory.New()
+	c := &counter{counts: map[string]float64{}, err: errors.New("boom")}
+
+	s := sample.New(h, 1, 0, time.Minute)
+	s.Telemetry = c
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, s.HandleLog(entry(log.LevelInfo, "upload", time.Unix(0, 0))))
+	}
+
+	assert.Equal(t, 1, len(h.Entries))
+	assert.Equal(t, map[string]float64{"log_sampled": 4}, c.counts)
+	assert.Equal(t, 1, strings.Count(buf.String(), "log/sample: failed to increment log_sampled: boom"))
+}
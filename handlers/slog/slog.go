@@ -0,0 +1,177 @@
+// Package slog bridges log/slog and this package in both directions:
+// Handler writes entries into a slog.Handler, and Adapter is a
+// slog.Handler forwarding records into a log.Logger.
+package slog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sayden/log"
+)
+
+// Levels mapping.
+var Levels = map[log.Level]slog.Level{
+	log.LevelTrace:    slog.LevelDebug - 4,
+	log.LevelDebug:    slog.LevelDebug,
+	log.LevelInfo:     slog.LevelInfo,
+	log.LevelNotice:   slog.LevelInfo + 2,
+	log.LevelWarn:     slog.LevelWarn,
+	log.LevelError:    slog.LevelError,
+	log.LevelCritical: slog.LevelError + 4,
+	log.LevelPanic:    slog.LevelError + 8,
+	log.LevelFatal:    slog.LevelError + 12,
+}
+
+// Level returns the log.Level of the slog level `l`.
+func Level(l slog.Level) log.Level {
+	switch {
+	case l < slog.LevelDebug:
+		return log.LevelTrace
+	case l < slog.LevelInfo:
+		return log.LevelDebug
+	case l < slog.LevelInfo+2:
+		return log.LevelInfo
+	case l < slog.LevelWarn:
+		return log.LevelNotice
+	case l < slog.LevelError:
+		return log.LevelWarn
+	case l < slog.LevelError+4:
+		return log.LevelError
+	default:
+		return log.LevelCritical
+	}
+}
+
+// Handler implementation.
+type Handler struct {
+	Handler slog.Handler
+}
+
+// New handler writing into `h`.
+func New(h slog.Handler) *Handler {
+	return &Handler{
+		Handler: h,
+	}
+}
+
+// HandleLog implements log.Handler.
+func (h *Handler) HandleLog(e log.Interface) error {
+	ctx := e.GetContext()
+
+	level, ok := Levels[e.GetLevel()]
+	if !ok {
+		return log.ErrInvalidLevel
+	}
+
+	if !h.Handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	r := slog.NewRecord(e.GetTimestamp(), level, e.GetMessage(), 0)
+
+	for _, name := range e.GetFieldNames() {
+		r.AddAttrs(slog.Any(name, e.GetFields().Get(name)))
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+// Adapter implements slog.Handler, forwarding records into a
+// log.Interface such as a *log.Logger, so they go through its level,
+// hooks and handlers like its own entries. Attributes become fields,
+// with the keys of groups joined by a dot such as "request.method".
+//
+// The time and PC of records are not carried over: entries are stamped
+// by the Logger's Clock when forwarded, and with AddCaller the "caller"
+// field is a frame of this package rather than the caller of slog.
+type Adapter struct {
+	Logger log.Interface
+	fields log.Fields
+	prefix string
+}
+
+// NewAdapter returns an Adapter forwarding records into `l`. To forward
+// into a log.Handler, pass a *log.Logger with it as the Handler.
+func NewAdapter(l log.Interface) *Adapter {
+	return &Adapter{
+		Logger: l,
+	}
+}
+
+// Enabled implements slog.Handler.
+func (a *Adapter) Enabled(_ context.Context, l slog.Level) bool {
+	return a.Logger.Enabled(Level(l))
+}
+
+// Handle implements slog.Handler.
+func (a *Adapter) Handle(ctx context.Context, r slog.Record) error {
+	fields := make(log.Fields, len(a.fields)+r.NumAttrs())
+
+	for k, v := range a.fields {
+		fields[k] = v
+	}
+
+	r.Attrs(func(attr slog.Attr) bool {
+		addAttr(fields, a.prefix, attr)
+		return true
+	})
+
+	a.Logger.WithContext(ctx).WithFields(fields).Log(Level(r.Level), r.Message)
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (a *Adapter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(log.Fields, len(a.fields)+len(attrs))
+
+	for k, v := range a.fields {
+		fields[k] = v
+	}
+
+	for _, attr := range attrs {
+		addAttr(fields, a.prefix, attr)
+	}
+
+	return &Adapter{
+		Logger: a.Logger,
+		fields: fields,
+		prefix: a.prefix,
+	}
+}
+
+// WithGroup implements slog.Handler.
+func (a *Adapter) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return a
+	}
+
+	return &Adapter{
+		Logger: a.Logger,
+		fields: a.fields,
+		prefix: a.prefix + name + ".",
+	}
+}
+
+// addAttr sets the field of `attr` in `fields`, following the rules of
+// slog.Handler for empty attributes and groups.
+func addAttr(fields log.Fields, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() != slog.KindGroup {
+		fields[prefix+attr.Key] = attr.Value.Any()
+		return
+	}
+
+	if attr.Key != "" {
+		prefix += attr.Key + "."
+	}
+
+	for _, a := range attr.Value.Group() {
+		addAttr(fields, prefix, a)
+	}
+}
@@ -0,0 +1,118 @@
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	stdslog "log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/memory"
+	"github.com/sayden/log/handlers/slog"
+)
+
+func init() {
+	log.Now = func() time.Time {
+		return time.Unix(0, 0).UTC()
+	}
+}
+
+type ctxKey struct{}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := stdslog.NewJSONHandler(&buf, &stdslog.HandlerOptions{
+		Level: stdslog.LevelDebug,
+	})
+
+	l := &log.Logger{
+		Handler: slog.New(h),
+		Level:   log.LevelTrace,
+	}
+
+	l.WithField("user", "tj").WithField("id", "123").Info("hello")
+	l.Log(log.LevelTrace, "ignored")
+	l.Notice("world")
+	l.Error("boom")
+
+	expected := `{"time":"1970-01-01T00:00:00Z","level":"INFO","msg":"hello","id":"123","user":"tj"}
+{"time":"1970-01-01T00:00:00Z","level":"INFO+2","msg":"world"}
+{"time":"1970-01-01T00:00:00Z","level":"ERROR","msg":"boom"}
+`
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestAdapter(t *testing.T) {
+	h := memory.New()
+	l := stdslog.New(slog.NewAdapter(&log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}))
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "abc")
+
+	l.Debug("ignored")
+	l.With("app", "uploads").
+		WithGroup("request").
+		With("method", "PUT").
+		InfoContext(ctx, "upload", "path", "/sloth.png", stdslog.Group("user", "name", "tobi"), stdslog.Group("empty"))
+	l.Warn("retry", stdslog.Int("attempt", 2))
+
+	assert.Equal(t, 2, len(h.Entries))
+
+	e := h.Entries[0]
+	assert.Equal(t, "upload", e.GetMessage())
+	assert.Equal(t, log.LevelInfo, e.GetLevel())
+	assert.Equal(t, "abc", e.GetContext().Value(ctxKey{}))
+	assert.False(t, e.GetTimestamp().IsZero())
+	assert.Equal(t, log.Fields{
+		"app":               "uploads",
+		"request.method":    "PUT",
+		"request.path":      "/sloth.png",
+		"request.user.name": "tobi",
+	}, e.GetFields())
+
+	e = h.Entries[1]
+	assert.Equal(t, log.LevelWarn, e.GetLevel())
+	assert.Equal(t, log.Fields{"attempt": int64(2)}, e.GetFields())
+}
+
+func TestAdapter_logger(t *testing.T) {
+	h := memory.New()
+
+	logger := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	logger.AddHook(&log.Redactor{Keys: []string{"password"}})
+	logger.SetLevelOverrides("db=warn")
+
+	l := stdslog.New(slog.NewAdapter(logger.Named("db").WithField("app", "uploads")))
+	l.Info("ignored")
+	l.Warn("login", "user", "tobi", "password", "hunter2")
+
+	assert.Equal(t, 1, len(h.Entries))
+	assert.Equal(t, log.Fields{
+		"app":      "uploads",
+		"logger":   "db",
+		"user":     "tobi",
+		"password": log.DefaultMask,
+	}, h.Entries[0].GetFields())
+}
+
+func TestHandler_invalidLevel(t *testing.T) {
+	h := slog.New(stdslog.NewJSONHandler(&bytes.Buffer{}, nil))
+	assert.ErrorIs(t, h.HandleLog(&log.Entry{Level: log.InvalidLevel}), log.ErrInvalidLevel)
+}
+
+func TestLevel(t *testing.T) {
+	for l := log.LevelTrace; l <= log.LevelCritical; l++ {
+		assert.Equal(t, l, slog.Level(slog.Levels[l]))
+	}
+}
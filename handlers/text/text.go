@@ -14,9 +14,6 @@ import (
 // Default handler outputting to stderr.
 var Default = New(os.Stderr)
 
-// start time.
-var start = time.Now()
-
 // colors.
 const (
 	none   = 0
@@ -28,27 +25,36 @@ const (
 )
 
 // Colors mapping.
-var Colors = [...]int{
-	log.LevelDebug: gray,
-	log.LevelInfo:  blue,
-	log.LevelWarn:  yellow,
-	log.LevelError: red,
-	log.LevelFatal: red,
+var Colors = map[log.Level]int{
+	log.LevelTrace:    gray,
+	log.LevelDebug:    gray,
+	log.LevelInfo:     blue,
+	log.LevelNotice:   green,
+	log.LevelWarn:     yellow,
+	log.LevelError:    red,
+	log.LevelCritical: red,
+	log.LevelPanic:    red,
+	log.LevelFatal:    red,
 }
 
 // Strings mapping.
-var Strings = [...]string{
-	log.LevelDebug: "DEBUG",
-	log.LevelInfo:  "INFO",
-	log.LevelWarn:  "WARN",
-	log.LevelError: "ERROR",
-	log.LevelFatal: "FATAL",
+var Strings = map[log.Level]string{
+	log.LevelTrace:    "TRACE",
+	log.LevelDebug:    "DEBUG",
+	log.LevelInfo:     "INFO",
+	log.LevelNotice:   "NOTICE",
+	log.LevelWarn:     "WARN",
+	log.LevelError:    "ERROR",
+	log.LevelCritical: "CRIT",
+	log.LevelPanic:    "PANIC",
+	log.LevelFatal:    "FATAL",
 }
 
 // Handler implementation.
 type Handler struct {
 	mu     sync.Mutex
 	Writer io.Writer
+	start  time.Time
 }
 
 // New handler.
@@ -62,19 +68,52 @@ func New(w io.Writer) *Handler {
 func (h *Handler) HandleLog(e log.Interface) error {
 	color := Colors[e.GetLevel()]
 	level := Strings[e.GetLevel()]
-	names := e.GetFields().Names()
+	names := e.GetFieldNames()
 
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	ts := time.Since(start) / time.Second
+	// seconds since the first entry, on the clock of the Logger.
+	if h.start.IsZero() {
+		h.start = e.GetTimestamp()
+	}
+
+	ts := e.GetTimestamp().Sub(h.start) / time.Second
 	fmt.Fprintf(h.Writer, "\033[%dm%6s\033[0m[%04d] %-25s", color, level, ts, e.GetMessage())
 
+	var chains []log.ErrorChain
+
 	for _, name := range names {
-		fmt.Fprintf(h.Writer, " \033[%dm%s\033[0m=%v", color, name, e.GetFields().Get(name))
+		v := e.GetFields().Get(name)
+
+		if c, ok := v.(log.ErrorChain); ok {
+			chains = append(chains, c)
+			continue
+		}
+
+		fmt.Fprintf(h.Writer, " \033[%dm%s\033[0m=%v", color, name, v)
 	}
 
 	fmt.Fprintln(h.Writer)
 
+	for _, c := range chains {
+		c.WriteIndented(h.Writer, 7)
+	}
+
 	return nil
 }
+
+func init() {
+	log.RegisterHandler("text", func(c log.HandlerConfig) (log.Handler, error) {
+		if err := c.Decode(&struct{}{}); err != nil {
+			return nil, err
+		}
+
+		w, err := c.Writer()
+		if err != nil {
+			return nil, err
+		}
+
+		return c.Closing(New(w), w), nil
+	})
+}
@@ -2,9 +2,11 @@ package text_test
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/sayden/log"
@@ -29,3 +31,88 @@ func Test(t *testing.T) {
 
 	assert.Equal(t, expected, buf.String())
 }
+
+func TestCollisions(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log.Logger{
+		Handler: text.New(&buf),
+		Level:   log.LevelInfo,
+	}
+
+	l.WithFields(log.Fields{"level": "debug", "message": "hi"}).Info("hello")
+
+	expected := "\x1b[34m  INFO\x1b[0m[0000] hello                     \x1b[34mlevel\x1b[0m=debug \x1b[34mmessage\x1b[0m=hi\n"
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log.Logger{
+		Handler: text.New(&buf),
+		Level:   log.LevelTrace,
+	}
+
+	for level := log.LevelTrace; level < log.LevelPanic; level++ {
+		l.Log(level, "hello")
+	}
+
+	assert.Contains(t, buf.String(), "TRACE")
+	assert.Contains(t, buf.String(), "NOTICE")
+	assert.Contains(t, buf.String(), "CRIT")
+}
+
+func TestErrorStack(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log.Logger{
+		Handler:    text.New(&buf),
+		Level:      log.LevelInfo,
+		ErrorStack: true,
+	}
+
+	l.WithError(errors.Wrap(errors.New("unauthorized"), "uploading")).Error("upload failed")
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Contains(t, lines[0], "upload failed")
+	assert.NotContains(t, lines[0], "stack")
+	assert.Equal(t, "       *errors.withStack: uploading: unauthorized", lines[1])
+	assert.Equal(t, "           github.com/sayden/log/handlers/text_test.TestErrorStack", lines[2])
+	assert.Contains(t, lines[3], "text_test.go:")
+	assert.Contains(t, buf.String(), "       *errors.withMessage: uploading: unauthorized\n")
+	assert.Contains(t, buf.String(), "       *errors.fundamental: unauthorized\n")
+}
+
+func TestClock(t *testing.T) {
+	var buf bytes.Buffer
+
+	clock := log.NewFakeClock(time.Unix(100, 0))
+
+	l := &log.Logger{
+		Handler: text.New(&buf),
+		Level:   log.LevelInfo,
+		Clock:   clock,
+	}
+
+	l.Info("hello")
+	clock.Add(5 * time.Second)
+	l.Info("world")
+
+	assert.Contains(t, buf.String(), "[0000] hello")
+	assert.Contains(t, buf.String(), "[0005] world")
+}
+
+func TestHandler_literal(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log.Logger{
+		Handler: &text.Handler{Writer: &buf},
+		Level:   log.LevelInfo,
+	}
+
+	l.Info("hello")
+
+	assert.Contains(t, buf.String(), "[0000] hello")
+}
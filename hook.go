@@ -0,0 +1,82 @@
+package log
+
+import "errors"
+
+// ErrDiscard is returned by a Hook to drop the entry, so it is not
+// passed to the handler nor to the remaining hooks.
+var ErrDiscard = errors.New("log: entry discarded")
+
+// Hook is run on every finalized entry before it is handled, and may
+// add fields, rewrite the message or discard the entry with ErrDiscard.
+// Other errors are reported and the entry is still handled.
+//
+// Unlike handlers, hooks apply whichever handler is installed, which
+// suits enrichment such as the hostname or build version.
+type Hook interface {
+	Fire(*Entry) error
+}
+
+// The HookFunc type is an adapter to allow the use of ordinary functions
+// as hooks.
+type HookFunc func(*Entry) error
+
+// Fire calls f(e).
+func (f HookFunc) Fire(e *Entry) error {
+	return f(e)
+}
+
+// levelHook is a hook limited to some levels.
+type levelHook struct {
+	hook   Hook
+	levels []Level
+}
+
+// fires returns whether the hook applies to `level`.
+func (h levelHook) fires(level Level) bool {
+	if len(h.levels) == 0 {
+		return true
+	}
+
+	for _, l := range h.levels {
+		if l == level {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddHook adds `h`, run for entries of `levels`, or of every level
+// when none is passed. Hooks run in the order they were added, and
+// are shared by named loggers.
+func (l *Logger) AddHook(h Hook, levels ...Level) {
+	r := l.root()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hooks := make([]levelHook, 0, len(r.hooks)+1)
+	hooks = append(hooks, r.hooks...)
+	r.hooks = append(hooks, levelHook{hook: h, levels: levels})
+}
+
+// fire runs the hooks for the entry, returning false when it is discarded.
+func (l *Logger) fire(e *Entry) bool {
+	r := l.root()
+	r.mu.RLock()
+	hooks := r.hooks
+	r.mu.RUnlock()
+
+	for _, h := range hooks {
+		if !h.fires(e.Level) {
+			continue
+		}
+
+		if err := h.hook.Fire(e); err == ErrDiscard {
+			return false
+		} else if err != nil {
+			std().Printf("error firing hook: %s", err)
+		}
+	}
+
+	return true
+}
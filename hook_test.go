@@ -0,0 +1,105 @@
+package log_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/memory"
+)
+
+func TestLogger_AddHook(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.AddHook(log.HookFunc(func(e *log.Entry) error {
+		e.Fields["hostname"] = "sloth"
+		return nil
+	}))
+
+	l.AddHook(log.HookFunc(func(e *log.Entry) error {
+		e.Message = "[uploads] " + e.Message
+		return nil
+	}))
+
+	l.Named("db").WithField("file", "sloth.png").Info("upload")
+
+	assert.Equal(t, 1, len(h.Entries))
+	e := h.Entries[0]
+	assert.Equal(t, "[uploads] upload", e.GetMessage())
+	assert.Equal(t, log.Fields{"file": "sloth.png", "hostname": "sloth", "logger": "db"}, e.GetFields())
+}
+
+func TestLogger_AddHook_levels(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelDebug,
+	}
+
+	var fired []log.Level
+	l.AddHook(log.HookFunc(func(e *log.Entry) error {
+		fired = append(fired, e.Level)
+		return nil
+	}), log.LevelWarn, log.LevelError)
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+
+	assert.Equal(t, 4, len(h.Entries))
+	assert.Equal(t, []log.Level{log.LevelWarn, log.LevelError}, fired)
+}
+
+func TestLogger_AddHook_discard(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	var fired int
+	l.AddHook(log.HookFunc(func(e *log.Entry) error {
+		if e.Fields["health"] != nil {
+			return log.ErrDiscard
+		}
+		return nil
+	}))
+	l.AddHook(log.HookFunc(func(e *log.Entry) error {
+		fired++
+		return nil
+	}))
+
+	l.WithField("health", true).Info("ping")
+	l.Info("upload")
+
+	assert.Equal(t, 1, len(h.Entries))
+	assert.Equal(t, "upload", h.Entries[0].GetMessage())
+	assert.Equal(t, 1, fired)
+}
+
+func TestLogger_AddHook_error(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.AddHook(log.HookFunc(func(e *log.Entry) error {
+		return errors.New("boom")
+	}))
+
+	l.Info("upload")
+
+	assert.Equal(t, 1, len(h.Entries))
+}
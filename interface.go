@@ -1,6 +1,7 @@
 package log
 
 import (
+	"context"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -11,22 +12,45 @@ type Interface interface {
 	WithFields(fields Fielder) Interface
 	WithField(key string, value interface{}) Interface
 	WithError(err error) Interface
+	With(fields ...Field) Interface
+	WithContext(ctx context.Context) Interface
+	Log(level Level, msg string)
 	Debug(msg string)
 	Info(msg string)
+	Notice(msg string)
 	Warn(msg string)
 	Error(msg string)
+	Critical(msg string)
+	Panic(msg string)
 	Fatal(msg string)
+	Logf(level Level, msg string, v ...interface{})
 	Debugf(msg string, v ...interface{})
 	Infof(msg string, v ...interface{})
+	Noticef(msg string, v ...interface{})
 	Warnf(msg string, v ...interface{})
 	Errorf(msg string, v ...interface{})
+	Criticalf(msg string, v ...interface{})
+	Panicf(msg string, v ...interface{})
 	Fatalf(msg string, v ...interface{})
+	LogKV(level Level, msg string, kv ...interface{})
+	DebugKV(msg string, kv ...interface{})
+	InfoKV(msg string, kv ...interface{})
+	NoticeKV(msg string, kv ...interface{})
+	WarnKV(msg string, kv ...interface{})
+	ErrorKV(msg string, kv ...interface{})
+	CriticalKV(msg string, kv ...interface{})
+	PanicKV(msg string, kv ...interface{})
+	FatalKV(msg string, kv ...interface{})
 	Trace(msg string) Interface
+	Enabled(level Level) bool
+	V(n int) Interface
+	TraceSlow(msg string, threshold time.Duration) Interface
 	addons
 	telemetryAddons
 }
 
 type telemetryAddons interface {
+	WithTags(...string) Interface
 	Inc(string, float64) Interface
 }
 
@@ -35,7 +59,9 @@ type addons interface {
 	setStart(time time.Time)
 	GetLevel() Level
 	GetFields() Fields
+	GetFieldNames() []string
 	GetMessage() string
+	GetContext() context.Context
 	finalize(level Level, msg string) Interface
 	mergedFields() Fields
 	GetTimestamp() time.Time
@@ -43,9 +69,10 @@ type addons interface {
 	GetTelemetry() Telemetry
 }
 
+// Telemetry is implemented by the metrics backends. Tags are scoped to
+// the entry calling Inc, so they are passed along with every increment.
 type Telemetry interface {
-	WithTags(...string) Interface
-	Inc(name string, value float64) Interface
+	Inc(name string, value float64, tags ...string) error
 
 	//Prometheus
 	SetPrometheusInc(name string, counter *prometheus.CounterVec)
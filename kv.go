@@ -0,0 +1,39 @@
+package log
+
+// BadKey is the field key of the KV arguments which are not a key
+// followed by a value, as in log/slog.
+const BadKey = "!BADKEY"
+
+// kvFields returns the fields of `kv`, alternating string keys and
+// values. A Field counts as both the key and the value. Non-string keys
+// and a trailing key without value are collected under BadKey.
+func kvFields(kv []interface{}) []Field {
+	fields := make([]Field, 0, (len(kv)+1)/2)
+	var bad []interface{}
+
+	for i := 0; i < len(kv); i++ {
+		switch k := kv[i].(type) {
+		case Field:
+			fields = append(fields, k)
+		case string:
+			if i+1 == len(kv) {
+				bad = append(bad, k)
+				continue
+			}
+			fields = append(fields, Any(k, kv[i+1]))
+			i++
+		default:
+			bad = append(bad, k)
+		}
+	}
+
+	switch len(bad) {
+	case 0:
+	case 1:
+		fields = append(fields, Any(BadKey, bad[0]))
+	default:
+		fields = append(fields, Any(BadKey, bad))
+	}
+
+	return fields
+}
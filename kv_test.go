@@ -0,0 +1,59 @@
+package log_test
+
+import (
+	"testing"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_InfoKV(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelTrace,
+	}
+
+	l.WithField("app", "uploads").InfoKV("upload", "file", "sloth.png", "size", 1024)
+	l.LogKV(log.LevelTrace, "read", log.Int("bytes", 512), "file", "sloth.png")
+	l.WarnKV("retry")
+
+	assert.Equal(t, 3, len(h.Entries))
+	assert.Equal(t, log.LevelInfo, h.Entries[0].GetLevel())
+	assert.Equal(t, log.Fields{"app": "uploads", "file": "sloth.png", "size": 1024}, h.Entries[0].GetFields())
+	assert.Equal(t, log.LevelTrace, h.Entries[1].GetLevel())
+	assert.Equal(t, log.Fields{"bytes": 512, "file": "sloth.png"}, h.Entries[1].GetFields())
+	assert.Equal(t, log.Fields{}, h.Entries[2].GetFields())
+}
+
+func TestLogger_InfoKV_badKeys(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.ErrorKV("upload", "file", "sloth.png", "size")
+	l.ErrorKV("upload", 1, "file", "sloth.png", true)
+
+	assert.Equal(t, log.Fields{"file": "sloth.png", log.BadKey: "size"}, h.Entries[0].GetFields())
+	assert.Equal(t, log.Fields{"file": "sloth.png", log.BadKey: []interface{}{1, true}}, h.Entries[1].GetFields())
+}
+
+func TestInfoKV(t *testing.T) {
+	h := memory.New()
+	log.SetHandler(h)
+
+	log.InfoKV("upload", "file", "sloth.png")
+
+	assert.PanicsWithValue(t, "boom", func() {
+		log.PanicKV("boom", "file", "sloth.png")
+	})
+
+	assert.Equal(t, 2, len(h.Entries))
+	assert.Equal(t, log.Fields{"file": "sloth.png"}, h.Entries[0].GetFields())
+	assert.Equal(t, log.LevelPanic, h.Entries[1].GetLevel())
+}
@@ -0,0 +1,29 @@
+package log
+
+// Lazy is implemented by field values which are expensive to compute,
+// evaluated only when the entry is logged, so they cost nothing at a
+// disabled level. Values of type func() interface{} are evaluated too.
+type Lazy interface {
+	Eval() interface{}
+}
+
+// The LazyFunc type is an adapter to allow the use of ordinary functions
+// as Lazy values.
+type LazyFunc func() interface{}
+
+// Eval calls f().
+func (f LazyFunc) Eval() interface{} {
+	return f()
+}
+
+// eval returns the value of `v`, evaluating it when it is Lazy.
+func eval(v interface{}) interface{} {
+	switch l := v.(type) {
+	case Lazy:
+		return l.Eval()
+	case func() interface{}:
+		return l()
+	default:
+		return v
+	}
+}
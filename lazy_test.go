@@ -0,0 +1,56 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/json"
+	"github.com/sayden/log/handlers/memory"
+)
+
+func TestLazy(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	var evals int
+	dump := func() interface{} {
+		evals++
+		return "sloth.png"
+	}
+
+	ctx := l.WithField("dump", dump).WithFields(log.Fields{
+		"size": log.LazyFunc(func() interface{} {
+			evals++
+			return 1024
+		}),
+	})
+
+	ctx.Debug("upload")
+	assert.Equal(t, 0, evals)
+
+	ctx.Info("upload")
+	assert.Equal(t, 2, evals)
+
+	assert.Equal(t, 1, len(h.Entries))
+	assert.Equal(t, log.Fields{"dump": "sloth.png", "size": 1024}, h.Entries[0].GetFields())
+}
+
+func TestLazy_handler(t *testing.T) {
+	var buf bytes.Buffer
+
+	l := &log.Logger{
+		Handler: json.New(&buf),
+		Level:   log.LevelInfo,
+	}
+
+	l.With(log.Any("size", log.LazyFunc(func() interface{} { return 1024 }))).Info("upload")
+
+	assert.Contains(t, buf.String(), `"fields":{"size":1024}`)
+}
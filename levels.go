@@ -9,34 +9,47 @@ import (
 // ErrInvalidLevel is returned if the severity level is invalid.
 var ErrInvalidLevel = errors.New("invalid level")
 
-// Level of severity.
-type Level int
+// Level of severity. It is an int32 so Logger can store it atomically.
+type Level int32
 
-// Log levels.
+// Log levels. LevelDebug is the zero value, so a Logger without a Level
+// logs everything except LevelTrace.
 const (
-	InvalidLevel Level = iota - 1
+	InvalidLevel Level = iota - 2
+	LevelTrace
 	LevelDebug
 	LevelInfo
+	LevelNotice
 	LevelWarn
 	LevelError
+	LevelCritical
+	LevelPanic
 	LevelFatal
 )
 
-var levelNames = [...]string{
-	LevelDebug: "debug",
-	LevelInfo:  "info",
-	LevelWarn:  "warn",
-	LevelError: "error",
-	LevelFatal: "fatal",
+var levelNames = map[Level]string{
+	LevelTrace:    "trace",
+	LevelDebug:    "debug",
+	LevelInfo:     "info",
+	LevelNotice:   "notice",
+	LevelWarn:     "warn",
+	LevelError:    "error",
+	LevelCritical: "critical",
+	LevelPanic:    "panic",
+	LevelFatal:    "fatal",
 }
 
 var levelStrings = map[string]Level{
-	"debug":   LevelDebug,
-	"info":    LevelInfo,
-	"warn":    LevelWarn,
-	"warning": LevelWarn,
-	"error":   LevelError,
-	"fatal":   LevelFatal,
+	"trace":    LevelTrace,
+	"debug":    LevelDebug,
+	"info":     LevelInfo,
+	"notice":   LevelNotice,
+	"warn":     LevelWarn,
+	"warning":  LevelWarn,
+	"error":    LevelError,
+	"critical": LevelCritical,
+	"panic":    LevelPanic,
+	"fatal":    LevelFatal,
 }
 
 // String implementation.
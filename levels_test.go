@@ -13,12 +13,16 @@ func TestParseLevel(t *testing.T) {
 		Level  Level
 		Num    int
 	}{
+		{"trace", LevelTrace, -1},
 		{"debug", LevelDebug, 0},
 		{"info", LevelInfo, 1},
-		{"warn", LevelWarn, 2},
+		{"notice", LevelNotice, 2},
+		{"warn", LevelWarn, 3},
 		{"warning", LevelWarn, 3},
 		{"error", LevelError, 4},
-		{"fatal", LevelFatal, 5},
+		{"critical", LevelCritical, 5},
+		{"panic", LevelPanic, 6},
+		{"fatal", LevelFatal, 7},
 	}
 
 	for _, c := range cases {
@@ -26,6 +30,7 @@ func TestParseLevel(t *testing.T) {
 			l, err := ParseLevel(c.String)
 			assert.NoError(t, err, "parse")
 			assert.Equal(t, c.Level, l)
+			assert.Equal(t, c.Num, int(l))
 		})
 	}
 
@@ -43,7 +48,7 @@ func TestLevel_MarshalJSON(t *testing.T) {
 		Fields:  Fields{},
 	}
 
-	expect := `{"fields":{},"level":"info","timestamp":"0001-01-01T00:00:00Z","message":"hello","Telemetry":null}`
+	expect := `{"fields":{},"level":"info","timestamp":"0001-01-01T00:00:00Z","message":"hello"}`
 
 	b, err := json.Marshal(e)
 	assert.NoError(t, err)
@@ -58,3 +63,17 @@ func TestLevel_UnmarshalJSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, LevelInfo, e.Level)
 }
+
+func TestLevel_JSON_roundtrip(t *testing.T) {
+	for l := LevelTrace; l <= LevelFatal; l++ {
+		t.Run(l.String(), func(t *testing.T) {
+			b, err := json.Marshal(l)
+			assert.NoError(t, err)
+			assert.Equal(t, `"`+l.String()+`"`, string(b))
+
+			var v Level
+			assert.NoError(t, json.Unmarshal(b, &v))
+			assert.Equal(t, l, v)
+		})
+	}
+}
@@ -1,14 +1,20 @@
 package log
 
 import (
-	stdlog "log"
+	"context"
+	"os"
 	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // assert interface compliance.
 var _ Interface = (*Logger)(nil)
 
+// DefaultFlushTimeout is the FlushTimeout used when none is set.
+const DefaultFlushTimeout = 5 * time.Second
+
 // Fielder is an interface for providing fields to custom types.
 type Fielder interface {
 	Fields() Fields
@@ -56,29 +62,150 @@ type Handler interface {
 	HandleLog(Interface) error
 }
 
-// Logger represents a logger with configurable Level and Handler.
+// Logger represents a logger with configurable Level, Handler and Telemetry.
+//
+// The fields may be set when constructing the Logger. Once it is in use,
+// change them with SetLevel, SwapHandler and SetTelemetry, which are safe
+// to call while other goroutines are logging.
+//
+// Loggers returned by Named share the fields of the root Logger.
 type Logger struct {
-	Handler Handler
-	Level   Level
-	t       Telemetry
+	Handler   Handler
+	Level     Level
+	Telemetry Telemetry
+
+	// Clock stamps the entries and measures Trace durations, defaulting
+	// to SystemClock.
+	Clock Clock
+
+	// ExitFunc is called by Fatal after the handlers are flushed,
+	// defaulting to os.Exit, in which case they are closed instead.
+	ExitFunc func(code int)
+
+	// FlushTimeout bounds how long Fatal waits for the handlers to
+	// flush and close, defaulting to DefaultFlushTimeout.
+	FlushTimeout time.Duration
+
+	// AddCaller adds the "caller" field to entries, with the Frame
+	// calling into this package.
+	AddCaller bool
+
+	// CallerSkip is the number of frames to skip for AddCaller, for
+	// functions wrapping the logger.
+	CallerSkip int
+
+	// Verbosity is the highest n for which V(n) entries are logged.
+	Verbosity int32
+
+	// OrderedFields keeps the fields in the order they were added,
+	// instead of sorted, for the handlers using GetFieldNames. Later
+	// values of a key replace earlier ones in place.
+	OrderedFields bool
+
+	// ErrorStack makes WithError add the "stack" field, with the type
+	// and stack trace of every error in the chain.
+	ErrorStack bool
+
+	mu        sync.RWMutex
+	hooks     []levelHook
+	name      string
+	parent    *Logger
+	overrides atomic.Value
+}
+
+// SetLevel sets the log level.
+func (l *Logger) SetLevel(level Level) {
+	r := l.root()
+	atomic.StoreInt32((*int32)(&r.Level), int32(level))
+}
+
+// SetVerbosity sets the verbosity used by V.
+func (l *Logger) SetVerbosity(v int) {
+	r := l.root()
+	atomic.StoreInt32(&r.Verbosity, int32(v))
+}
+
+// GetVerbosity returns the verbosity used by V.
+func (l *Logger) GetVerbosity() int32 {
+	return atomic.LoadInt32(&l.root().Verbosity)
+}
+
+// Enabled returns whether messages at `level` are logged.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.GetLevel()
+}
+
+// V returns a new entry which only logs when `n` is at most Verbosity.
+func (l *Logger) V(n int) Interface {
+	return NewEntry(l, nil).V(n)
+}
+
+// SwapHandler sets the handler, returning the previous one.
+func (l *Logger) SwapHandler(h Handler) Handler {
+	r := l.root()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.Handler
+	r.Handler = h
+	return old
 }
 
-func (e *Logger) Inc(n string, v float64) Interface {
-	//first field value
-	e.t.Inc(n,v)
-	return e
+// SetTelemetry sets the telemetry.
+func (l *Logger) SetTelemetry(t Telemetry) {
+	r := l.root()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Telemetry = t
+}
+
+// clock returns the Clock of the root Logger.
+func (l *Logger) clock() Clock {
+	if c := l.root().Clock; c != nil {
+		return c
+	}
+
+	return SystemClock
+}
+
+// handler returns the current handler.
+func (l *Logger) handler() Handler {
+	r := l.root()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.Handler
+}
+
+// WithTags returns a new entry with `tags` used by Inc.
+func (l *Logger) WithTags(tags ...string) Interface {
+	return NewEntry(l, nil).WithTags(tags...)
+}
+
+// Inc increments the counter `n` by `v`, returning a new entry.
+func (l *Logger) Inc(n string, v float64) Interface {
+	return NewEntry(l, nil).Inc(n, v)
 }
 
 func (e *Logger) GetTelemetry() Telemetry {
-	return e.t
+	r := e.root()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.Telemetry
 }
 
 func (e *Logger) Stop(_ *error) {
 	Error("Stop Does nothing")
 }
 
+// GetLevel returns the level of the logger, taking the overrides set
+// with SetLevelOverrides into account for named loggers.
 func (e *Logger) GetLevel() Level {
-	return e.Level
+	r := e.root()
+
+	if level, ok := r.override(e.name); ok {
+		return level
+	}
+
+	return Level(atomic.LoadInt32((*int32)(&r.Level)))
 }
 
 func (e *Logger) GetMessage() string {
@@ -91,6 +218,11 @@ func (e *Logger) GetFields() Fields {
 	return nil
 }
 
+func (e *Logger) GetFieldNames() []string {
+	Error("GetFieldNames Does nothing")
+	return nil
+}
+
 func (e *Logger) GetTimestamp() time.Time {
 	Error("GetTimestamp Does nothing")
 	return time.Time{}
@@ -106,6 +238,10 @@ func (e *Logger) mergedFields() Fields {
 	return nil
 }
 
+func (e *Logger) GetContext() context.Context {
+	return context.Background()
+}
+
 func (e *Logger) SetMessage(msg string) {
 	Error("SetMessageDoes nothing")
 }
@@ -115,7 +251,7 @@ func (l *Logger) setStart(t time.Time) {
 
 // WithFields returns a new entry with `fields` set.
 func (l *Logger) WithFields(fields Fielder) Interface {
-	return NewEntry(l, l.t).WithFields(fields.Fields())
+	return NewEntry(l, nil).WithFields(fields.Fields())
 }
 
 // WithField returns a new entry with the `key` and `value` set.
@@ -123,79 +259,214 @@ func (l *Logger) WithFields(fields Fielder) Interface {
 // Note that the `key` should not have spaces in it - use camel
 // case or underscores
 func (l *Logger) WithField(key string, value interface{}) Interface {
-	return NewEntry(l, l.t).WithField(key, value)
+	return NewEntry(l, nil).WithField(key, value)
+}
+
+// With returns a new entry with the typed `fields` set.
+func (l *Logger) With(fields ...Field) Interface {
+	return NewEntry(l, nil).With(fields...)
 }
 
 // WithError returns a new entry with the "error" set to `err`.
 func (l *Logger) WithError(err error) Interface {
-	return NewEntry(l, l.t).WithError(err)
+	return NewEntry(l, nil).WithError(err)
+}
+
+// WithContext returns a new entry carrying `ctx`.
+func (l *Logger) WithContext(ctx context.Context) Interface {
+	return NewEntry(l, nil).WithContext(ctx)
+}
+
+// Log message at `level`.
+func (l *Logger) Log(level Level, msg string) {
+	NewEntry(l, nil).Log(level, msg)
 }
 
 // Debug level message.
 func (l *Logger) Debug(msg string) {
-	NewEntry(l, l.t).Debug(msg)
+	NewEntry(l, nil).Debug(msg)
 }
 
 // Info level message.
 func (l *Logger) Info(msg string) {
-	NewEntry(l, l.t).Info(msg)
+	NewEntry(l, nil).Info(msg)
+}
+
+// Notice level message.
+func (l *Logger) Notice(msg string) {
+	NewEntry(l, nil).Notice(msg)
 }
 
 // Warn level message.
 func (l *Logger) Warn(msg string) {
-	NewEntry(l, l.t).Warn(msg)
+	NewEntry(l, nil).Warn(msg)
 }
 
 // Error level message.
 func (l *Logger) Error(msg string) {
-	NewEntry(l, l.t).Error(msg)
+	NewEntry(l, nil).Error(msg)
+}
+
+// Critical level message.
+func (l *Logger) Critical(msg string) {
+	NewEntry(l, nil).Critical(msg)
+}
+
+// Panic level message, followed by a panic.
+func (l *Logger) Panic(msg string) {
+	NewEntry(l, nil).Panic(msg)
 }
 
 // Fatal level message, followed by an exit.
 func (l *Logger) Fatal(msg string) {
-	NewEntry(l, l.t).Fatal(msg)
+	NewEntry(l, nil).Fatal(msg)
+}
+
+// Logf formatted message at `level`.
+func (l *Logger) Logf(level Level, msg string, v ...interface{}) {
+	NewEntry(l, nil).Logf(level, msg, v...)
 }
 
 // Debugf level formatted message.
 func (l *Logger) Debugf(msg string, v ...interface{}) {
-	NewEntry(l, l.t).Debugf(msg, v...)
+	NewEntry(l, nil).Debugf(msg, v...)
 }
 
 // Infof level formatted message.
 func (l *Logger) Infof(msg string, v ...interface{}) {
-	NewEntry(l, l.t).Infof(msg, v...)
+	NewEntry(l, nil).Infof(msg, v...)
+}
+
+// Noticef level formatted message.
+func (l *Logger) Noticef(msg string, v ...interface{}) {
+	NewEntry(l, nil).Noticef(msg, v...)
 }
 
 // Warnf level formatted message.
 func (l *Logger) Warnf(msg string, v ...interface{}) {
-	NewEntry(l, l.t).Warnf(msg, v...)
+	NewEntry(l, nil).Warnf(msg, v...)
 }
 
 // Errorf level formatted message.
 func (l *Logger) Errorf(msg string, v ...interface{}) {
-	NewEntry(l, l.t).Errorf(msg, v...)
+	NewEntry(l, nil).Errorf(msg, v...)
+}
+
+// Criticalf level formatted message.
+func (l *Logger) Criticalf(msg string, v ...interface{}) {
+	NewEntry(l, nil).Criticalf(msg, v...)
+}
+
+// Panicf level formatted message, followed by a panic.
+func (l *Logger) Panicf(msg string, v ...interface{}) {
+	NewEntry(l, nil).Panicf(msg, v...)
 }
 
 // Fatalf level formatted message, followed by an exit.
 func (l *Logger) Fatalf(msg string, v ...interface{}) {
-	NewEntry(l, l.t).Fatalf(msg, v...)
+	NewEntry(l, nil).Fatalf(msg, v...)
+}
+
+// LogKV message at `level` with the alternating keys and values of `kv`.
+func (l *Logger) LogKV(level Level, msg string, kv ...interface{}) {
+	NewEntry(l, nil).LogKV(level, msg, kv...)
+}
+
+// DebugKV level message with key-value fields.
+func (l *Logger) DebugKV(msg string, kv ...interface{}) {
+	NewEntry(l, nil).DebugKV(msg, kv...)
+}
+
+// InfoKV level message with key-value fields.
+func (l *Logger) InfoKV(msg string, kv ...interface{}) {
+	NewEntry(l, nil).InfoKV(msg, kv...)
+}
+
+// NoticeKV level message with key-value fields.
+func (l *Logger) NoticeKV(msg string, kv ...interface{}) {
+	NewEntry(l, nil).NoticeKV(msg, kv...)
+}
+
+// WarnKV level message with key-value fields.
+func (l *Logger) WarnKV(msg string, kv ...interface{}) {
+	NewEntry(l, nil).WarnKV(msg, kv...)
+}
+
+// ErrorKV level message with key-value fields.
+func (l *Logger) ErrorKV(msg string, kv ...interface{}) {
+	NewEntry(l, nil).ErrorKV(msg, kv...)
+}
+
+// CriticalKV level message with key-value fields.
+func (l *Logger) CriticalKV(msg string, kv ...interface{}) {
+	NewEntry(l, nil).CriticalKV(msg, kv...)
+}
+
+// PanicKV level message with key-value fields, followed by a panic.
+func (l *Logger) PanicKV(msg string, kv ...interface{}) {
+	NewEntry(l, nil).PanicKV(msg, kv...)
+}
+
+// FatalKV level message with key-value fields, followed by an exit.
+func (l *Logger) FatalKV(msg string, kv ...interface{}) {
+	NewEntry(l, nil).FatalKV(msg, kv...)
 }
 
 // Trace returns a new entry with a Stop method to fire off
 // a corresponding completion log, useful with defer.
 func (l *Logger) Trace(msg string) Interface {
-	return NewEntry(l, l.t).Trace(msg)
+	return NewEntry(l, nil).Trace(msg)
+}
+
+// TraceSlow returns a new entry with a Stop method firing off a
+// completion log only when it takes `threshold` or longer, or fails.
+func (l *Logger) TraceSlow(msg string, threshold time.Duration) Interface {
+	return NewEntry(l, nil).TraceSlow(msg, threshold)
 }
 
 // log the message, invoking the handler. We clone the entry here
 // to bypass the overhead in Entry methods when the level is not
 // met.
 func (l *Logger) log(level Level, e *Entry, msg string) {
-	if level < l.Level {
+	if e.disabled || level < l.GetLevel() {
 		return
 	}
 
-	if err := l.Handler.HandleLog(e.finalize(level, msg)); err != nil {
-		stdlog.Printf("error logging: %s", err)
+	entry := e.finalize(level, msg).(*Entry)
+	if !l.fire(entry) {
+		return
 	}
+
+	if err := l.handler().HandleLog(entry); err != nil {
+		std().Printf("error logging: %s", err)
+	}
+}
+
+// exit calls ExitFunc with `code`. When it is os.Exit the handlers are
+// closed first, so Closers such as graylog send what they queued.
+// Otherwise they are only flushed, so the Logger is still usable when
+// ExitFunc returns, as in tests.
+func (l *Logger) exit(code int) {
+	r := l.root()
+
+	timeout := r.FlushTimeout
+	if timeout == 0 {
+		timeout = DefaultFlushTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if r.ExitFunc == nil {
+		if err := closeHandlers(ctx, r.handler()); err != nil {
+			std().Printf("error closing: %s", err)
+		}
+		os.Exit(code)
+	}
+
+	if err := flushHandlers(ctx, r.handler()); err != nil {
+		std().Printf("error flushing: %s", err)
+	}
+
+	r.ExitFunc(code)
 }
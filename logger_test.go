@@ -1,13 +1,18 @@
 package log_test
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sayden/log"
 	"github.com/sayden/log/handlers/discard"
+	"github.com/sayden/log/handlers/level"
 	"github.com/sayden/log/handlers/memory"
+	"github.com/sayden/log/handlers/multi"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,6 +49,21 @@ func TestLogger_levels(t *testing.T) {
 	assert.Equal(t, e.GetLevel(), log.LevelInfo)
 }
 
+func TestLogger_zero(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+	}
+
+	l.Log(log.LevelTrace, "trace")
+	l.Debug("debug")
+
+	assert.Equal(t, log.LevelDebug, l.GetLevel())
+	assert.Equal(t, 1, len(h.Entries))
+	assert.Equal(t, "debug", h.Entries[0].GetMessage())
+}
+
 func TestLogger_WithFields(t *testing.T) {
 	h := memory.New()
 
@@ -103,7 +123,8 @@ func TestLogger_Trace_info(t *testing.T) {
 		e := h.Entries[0]
 		assert.Equal(t, e.GetMessage(), "upload")
 		assert.Equal(t, e.GetLevel(), log.LevelInfo)
-		assert.Equal(t, log.Fields{"file": "sloth.png"}, e.GetFields())
+		assert.Equal(t, "sloth.png", e.GetFields()["file"])
+		assert.Len(t, e.GetFields()["span_id"], 16)
 	}
 
 	{
@@ -165,7 +186,8 @@ func TestLogger_Trace_nil(t *testing.T) {
 		e := h.Entries[0]
 		assert.Equal(t, e.GetMessage(), "upload")
 		assert.Equal(t, e.GetLevel(), log.LevelInfo)
-		assert.Equal(t, log.Fields{"file": "sloth.png"}, e.GetFields())
+		assert.Equal(t, "sloth.png", e.GetFields()["file"])
+		assert.Len(t, e.GetFields()["span_id"], 16)
 	}
 
 	{
@@ -177,6 +199,106 @@ func TestLogger_Trace_nil(t *testing.T) {
 	}
 }
 
+func TestLogger_extendedLevels(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelTrace,
+	}
+
+	l.Log(log.LevelTrace, "trace")
+	l.Notice("notice")
+	l.Criticalf("critical %d", 1)
+	l.WithField("file", "sloth.png").Logf(log.LevelDebug, "debug %d", 2)
+
+	assert.Equal(t, 4, len(h.Entries))
+	assert.Equal(t, log.LevelTrace, h.Entries[0].GetLevel())
+	assert.Equal(t, log.LevelNotice, h.Entries[1].GetLevel())
+	assert.Equal(t, log.LevelCritical, h.Entries[2].GetLevel())
+	assert.Equal(t, "critical 1", h.Entries[2].GetMessage())
+	assert.Equal(t, log.LevelDebug, h.Entries[3].GetLevel())
+	assert.Equal(t, "debug 2", h.Entries[3].GetMessage())
+}
+
+func TestLogger_Panic(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	assert.PanicsWithValue(t, "boom", func() {
+		l.WithField("file", "sloth.png").Panic("boom")
+	})
+
+	assert.PanicsWithValue(t, "boom 2", func() {
+		l.Panicf("boom %d", 2)
+	})
+
+	assert.Equal(t, 2, len(h.Entries))
+	assert.Equal(t, log.LevelPanic, h.Entries[0].GetLevel())
+	assert.Equal(t, log.Fields{"file": "sloth.png"}, h.Entries[0].GetFields())
+	assert.Equal(t, "boom 2", h.Entries[1].GetMessage())
+}
+
+type flusher struct {
+	memory.Handler
+	flushed int
+	block   chan struct{}
+}
+
+func (f *flusher) Flush() error {
+	if f.block != nil {
+		<-f.block
+	}
+	f.flushed++
+	return nil
+}
+
+func TestLogger_Fatal(t *testing.T) {
+	a := &flusher{}
+	b := &flusher{}
+
+	var code int
+
+	l := &log.Logger{
+		Handler:  multi.New(a, level.New(b, log.LevelError)),
+		Level:    log.LevelInfo,
+		ExitFunc: func(c int) { code = c },
+	}
+
+	l.WithField("file", "sloth.png").Fatalf("upload %s", "failed")
+
+	assert.Equal(t, 1, code)
+	assert.Equal(t, 1, a.flushed)
+	assert.Equal(t, 1, b.flushed)
+	assert.Equal(t, 1, len(a.Entries))
+	assert.Equal(t, log.LevelFatal, a.Entries[0].GetLevel())
+	assert.Equal(t, "upload failed", a.Entries[0].GetMessage())
+	assert.Equal(t, 1, len(b.Entries))
+}
+
+func TestLogger_Fatal_flushTimeout(t *testing.T) {
+	f := &flusher{block: make(chan struct{})}
+	defer close(f.block)
+
+	exited := false
+
+	l := &log.Logger{
+		Handler:      f,
+		Level:        log.LevelInfo,
+		ExitFunc:     func(int) { exited = true },
+		FlushTimeout: 10 * time.Millisecond,
+	}
+
+	l.Named("db").Fatal("boom")
+
+	assert.True(t, exited)
+	assert.Equal(t, 1, len(f.Entries))
+}
+
 func TestLogger_HandlerFunc(t *testing.T) {
 	h := memory.New()
 	f := func(e log.Interface) error {
@@ -195,6 +317,198 @@ func TestLogger_HandlerFunc(t *testing.T) {
 	assert.Equal(t, e.GetLevel(), log.LevelInfo)
 }
 
+type counter struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (c *counter) Inc(name string, value float64, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, fmt.Sprintf("%s %v %v", name, value, tags))
+	return nil
+}
+
+func (c *counter) SetPrometheusInc(string, *prometheus.CounterVec) {}
+
+func (c *counter) SetNamespace(string) {}
+
+func TestLogger_Telemetry(t *testing.T) {
+	h := memory.New()
+	c := &counter{}
+
+	l := &log.Logger{
+		Handler:   h,
+		Level:     log.LevelInfo,
+		Telemetry: c,
+	}
+
+	l.WithField("file", "sloth.png").WithTags("uploads").Inc("upload", 1).Info("upload")
+	l.WithTags("a").WithTags("b").Inc("upload", 2).WithField("file", "tobi.png").Info("upload")
+	l.Inc("upload", 3)
+
+	assert.Equal(t, []string{"upload 1 [uploads]", "upload 2 [a b]", "upload 3 []"}, c.calls)
+	assert.Equal(t, 2, len(h.Entries))
+	assert.Equal(t, log.Fields{"file": "sloth.png"}, h.Entries[0].GetFields())
+	assert.Equal(t, log.Fields{"file": "tobi.png"}, h.Entries[1].GetFields())
+}
+
+func TestNewEntry_telemetry(t *testing.T) {
+	a := &counter{}
+	b := &counter{}
+
+	l := &log.Logger{
+		Handler:   discard.New(),
+		Level:     log.LevelInfo,
+		Telemetry: a,
+	}
+
+	log.NewEntry(l, b).WithTags("uploads").Inc("upload", 1)
+	log.NewEntry(l, nil).Inc("upload", 2)
+
+	assert.Equal(t, []string{"upload 2 []"}, a.calls)
+	assert.Equal(t, []string{"upload 1 [uploads]"}, b.calls)
+}
+
+func TestLogger_Telemetry_concurrent(t *testing.T) {
+	c := &counter{}
+
+	l := &log.Logger{
+		Handler:   discard.New(),
+		Level:     log.LevelInfo,
+		Telemetry: c,
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tag := fmt.Sprintf("worker-%d", i)
+			for j := 0; j < 100; j++ {
+				l.WithTags(tag).Inc("jobs", 1)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1000, len(c.calls))
+	for i := 0; i < 10; i++ {
+		n := 0
+		for _, call := range c.calls {
+			if call == fmt.Sprintf("jobs 1 [worker-%d]", i) {
+				n++
+			}
+		}
+		assert.Equal(t, 100, n)
+	}
+}
+
+func TestLogger_SetLevel_concurrent(t *testing.T) {
+	l := &log.Logger{
+		Handler: discard.New(),
+		Level:   log.LevelInfo,
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.WithField("n", j).Debug("upload")
+				l.Info("upload")
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			l.SetLevel(log.LevelDebug)
+		} else {
+			l.SetLevel(log.LevelError)
+		}
+	}
+
+	wg.Wait()
+
+	l.SetLevel(log.LevelWarn)
+	assert.Equal(t, log.LevelWarn, l.GetLevel())
+}
+
+func TestLogger_SwapHandler_concurrent(t *testing.T) {
+	a := memory.New()
+	b := memory.New()
+
+	l := &log.Logger{
+		Handler: a,
+		Level:   log.LevelInfo,
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.WithField("n", j).Info("upload")
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			l.SwapHandler(b)
+		} else {
+			l.SwapHandler(a)
+		}
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1000, len(a.Entries)+len(b.Entries))
+	assert.Equal(t, a, l.SwapHandler(b))
+}
+
+func TestLogger_SetTelemetry_concurrent(t *testing.T) {
+	a := &counter{}
+	b := &counter{}
+
+	l := &log.Logger{
+		Handler:   discard.New(),
+		Level:     log.LevelInfo,
+		Telemetry: a,
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Inc("jobs", 1).Info("job")
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			l.SetTelemetry(b)
+		} else {
+			l.SetTelemetry(a)
+		}
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1000, len(a.calls)+len(b.calls))
+}
+
 func BenchmarkLogger_small(b *testing.B) {
 	l := &log.Logger{
 		Handler: discard.New(),
@@ -246,3 +560,189 @@ func BenchmarkLogger_large(b *testing.B) {
 			WithError(err).Error("upload failed")
 	}
 }
+
+func TestLogger_Trace_nested(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	func() {
+		parent := l.Trace("request")
+		defer parent.Stop(nil)
+
+		func() {
+			child := parent.WithField("file", "sloth.png").Trace("upload")
+			defer child.Stop(nil)
+			child.Info("resized")
+		}()
+	}()
+
+	assert.Equal(t, 5, len(h.Entries))
+
+	parent := h.Entries[0].GetFields()
+	child := h.Entries[1].GetFields()
+
+	assert.Nil(t, parent["parent_span_id"])
+	assert.NotEqual(t, parent["span_id"], child["span_id"])
+	assert.Equal(t, parent["span_id"], child["parent_span_id"])
+
+	for _, e := range h.Entries[1:4] {
+		assert.Equal(t, child["span_id"], e.GetFields()["span_id"])
+		assert.Equal(t, parent["span_id"], e.GetFields()["parent_span_id"])
+	}
+
+	assert.Equal(t, "request", h.Entries[4].GetMessage())
+	assert.Equal(t, parent["span_id"], h.Entries[4].GetFields()["span_id"])
+	assert.Nil(t, h.Entries[4].GetFields()["parent_span_id"])
+}
+
+func TestLogger_TraceSlow(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	func() (err error) {
+		defer l.TraceSlow("fast", time.Hour).Stop(&err)
+		return nil
+	}()
+
+	assert.Equal(t, 0, len(h.Entries))
+
+	func() (err error) {
+		defer l.TraceSlow("failed", time.Hour).Stop(&err)
+		return fmt.Errorf("boom")
+	}()
+
+	func() (err error) {
+		defer l.TraceSlow("slow", time.Millisecond).Stop(&err)
+		time.Sleep(2 * time.Millisecond)
+		return nil
+	}()
+
+	assert.Equal(t, 2, len(h.Entries))
+	assert.Equal(t, "failed", h.Entries[0].GetMessage())
+	assert.Equal(t, log.LevelError, h.Entries[0].GetLevel())
+	assert.Equal(t, "slow", h.Entries[1].GetMessage())
+	assert.Equal(t, log.LevelInfo, h.Entries[1].GetLevel())
+}
+
+func TestLogger_TraceSlow_derived(t *testing.T) {
+	h := memory.New()
+	clock := log.NewFakeClock(time.Unix(0, 0))
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+		Clock:   clock,
+	}
+
+	func() (err error) {
+		defer l.TraceSlow("fast", time.Hour).WithField("file", "sloth.png").Stop(&err)
+		clock.Add(time.Second)
+		return nil
+	}()
+
+	assert.Equal(t, 0, len(h.Entries))
+
+	func() (err error) {
+		defer l.TraceSlow("slow", time.Second).WithTags("uploads").WithContext(context.Background()).WithField("file", "sloth.png").Stop(&err)
+		clock.Add(2 * time.Second)
+		return nil
+	}()
+
+	func() (err error) {
+		defer l.Trace("upload").WithField("file", "tobi.png").Stop(&err)
+		clock.Add(time.Second)
+		return nil
+	}()
+
+	assert.Equal(t, 3, len(h.Entries))
+
+	e := h.Entries[0]
+	assert.Equal(t, "slow", e.GetMessage())
+	assert.Equal(t, 2*time.Second, e.GetFields()["duration"])
+	assert.Equal(t, "sloth.png", e.GetFields()["file"])
+
+	assert.Equal(t, "upload", h.Entries[1].GetMessage())
+
+	e = h.Entries[2]
+	assert.Equal(t, "upload", e.GetMessage())
+	assert.Equal(t, time.Second, e.GetFields()["duration"])
+	assert.Equal(t, "tobi.png", e.GetFields()["file"])
+}
+
+func TestLogger_Enabled(t *testing.T) {
+	l := &log.Logger{
+		Handler: memory.New(),
+		Level:   log.LevelInfo,
+	}
+
+	assert.False(t, l.Enabled(log.LevelDebug))
+	assert.True(t, l.Enabled(log.LevelInfo))
+	assert.True(t, l.WithField("file", "sloth.png").Enabled(log.LevelError))
+
+	assert.NoError(t, l.SetLevelOverrides("db=debug"))
+	assert.True(t, l.Named("db").Enabled(log.LevelDebug))
+}
+
+func TestLogger_V(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler:   h,
+		Level:     log.LevelInfo,
+		Verbosity: 1,
+	}
+
+	l.V(1).Info("one")
+	l.V(2).WithField("file", "sloth.png").Info("two")
+	l.Named("db").V(2).Error("two")
+
+	assert.False(t, l.V(2).Enabled(log.LevelError))
+	assert.True(t, l.V(1).Enabled(log.LevelInfo))
+
+	l.Named("db").SetVerbosity(2)
+	l.WithField("file", "sloth.png").V(2).Info("two")
+
+	assert.Equal(t, 2, len(h.Entries))
+	assert.Equal(t, "one", h.Entries[0].GetMessage())
+	assert.Equal(t, "two", h.Entries[1].GetMessage())
+	assert.Equal(t, log.Fields{"file": "sloth.png"}, h.Entries[1].GetFields())
+}
+
+type stringer struct {
+	calls *int
+}
+
+func (s stringer) String() string {
+	*s.calls++
+	return "sloth"
+}
+
+func TestLogger_formatted_disabled(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelWarn,
+	}
+
+	var calls int
+	s := stringer{&calls}
+
+	l.Debugf("upload %s", s)
+	l.WithField("file", "sloth.png").Infof("upload %s", s)
+	l.Logf(log.LevelNotice, "upload %s", s)
+	l.V(1).Errorf("upload %s", s)
+	assert.Equal(t, 0, calls)
+
+	l.Warnf("upload %s", s)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "upload sloth", h.Entries[0].GetMessage())
+}
@@ -0,0 +1,111 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+)
+
+// levelOverride is a level applied to the named loggers matching pattern.
+type levelOverride struct {
+	pattern string
+	level   Level
+}
+
+// match returns whether `name` matches the override, and how specific the
+// match is. A pattern matches its name and all of its children, with or
+// without a ".*" suffix, so longer patterns are more specific.
+func (o levelOverride) match(name string) (int, bool) {
+	if o.pattern == "*" {
+		return 0, true
+	}
+
+	prefix := strings.TrimSuffix(o.pattern, ".*")
+	if name == prefix || strings.HasPrefix(name, prefix+".") {
+		return len(prefix) + 1, true
+	}
+
+	return 0, false
+}
+
+// Named returns a child logger which adds the "logger" field to its
+// entries. Names nest with a dot, so Named("db").Named("pool") is
+// "db.pool". Child loggers share the Handler, Level and Telemetry of
+// the root Logger.
+func (l *Logger) Named(name string) *Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	return &Logger{
+		name:   name,
+		parent: l.root(),
+	}
+}
+
+// Name returns the name of the logger, empty for the root Logger.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// SetLevelOverrides sets levels for named loggers from `spec`, a comma
+// separated list of pattern=level pairs such as "db.*=debug,http=warn".
+// A pattern is a name, optionally followed by ".*", matching that logger
+// and all of its children, or "*" matching every named logger. The most
+// specific pattern wins, and loggers not matching any pattern use Level.
+// An empty spec removes all overrides.
+func (l *Logger) SetLevelOverrides(spec string) error {
+	var overrides []levelOverride
+
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return fmt.Errorf("invalid level override %q", pair)
+		}
+
+		level, err := ParseLevel(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return fmt.Errorf("invalid level override %q: %s", pair, err)
+		}
+
+		overrides = append(overrides, levelOverride{
+			pattern: strings.TrimSpace(parts[0]),
+			level:   level,
+		})
+	}
+
+	l.root().overrides.Store(overrides)
+	return nil
+}
+
+// override returns the level of the most specific override matching `name`,
+// the last one of equally specific overrides.
+func (l *Logger) override(name string) (Level, bool) {
+	if name == "" {
+		return InvalidLevel, false
+	}
+
+	overrides, _ := l.overrides.Load().([]levelOverride)
+
+	level, best := InvalidLevel, -1
+	for _, o := range overrides {
+		if n, ok := o.match(name); ok && n >= best {
+			level, best = o.level, n
+		}
+	}
+
+	return level, best >= 0
+}
+
+// root returns the Logger that named loggers share their settings with.
+func (l *Logger) root() *Logger {
+	if l.parent != nil {
+		return l.parent
+	}
+
+	return l
+}
@@ -0,0 +1,119 @@
+package log_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_Named(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	db := l.Named("db")
+	pool := db.Named("pool")
+
+	assert.Equal(t, "db", db.Name())
+	assert.Equal(t, "db.pool", pool.Name())
+
+	l.Info("start")
+	db.WithField("table", "users").Info("query")
+	pool.Info("acquire")
+	pool.WithField("logger", "custom").Info("release")
+
+	assert.Equal(t, 4, len(h.Entries))
+	assert.Equal(t, log.Fields{}, h.Entries[0].GetFields())
+	assert.Equal(t, log.Fields{"logger": "db", "table": "users"}, h.Entries[1].GetFields())
+	assert.Equal(t, log.Fields{"logger": "db.pool"}, h.Entries[2].GetFields())
+	assert.Equal(t, log.Fields{"logger": "custom"}, h.Entries[3].GetFields())
+}
+
+func TestLogger_Named_shared(t *testing.T) {
+	a := memory.New()
+	b := memory.New()
+
+	l := &log.Logger{
+		Handler: a,
+		Level:   log.LevelInfo,
+	}
+
+	db := l.Named("db")
+	db.Debug("query")
+	db.Info("query")
+
+	l.SwapHandler(b)
+	l.SetLevel(log.LevelDebug)
+	db.Debug("query")
+
+	assert.Equal(t, 1, len(a.Entries))
+	assert.Equal(t, 1, len(b.Entries))
+	assert.Equal(t, log.LevelDebug, db.GetLevel())
+}
+
+func TestLogger_SetLevelOverrides(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	err := l.SetLevelOverrides("db.*=debug, http=warn, db.pool.*=error, db.pool.conn=info")
+	assert.NoError(t, err)
+
+	cases := []struct {
+		Name  string
+		Level log.Level
+	}{
+		{"db", log.LevelDebug},
+		{"db.query", log.LevelDebug},
+		{"db.pool", log.LevelError},
+		{"db.pool.idle", log.LevelError},
+		{"db.pool.conn", log.LevelInfo},
+		{"db.pool.conn.tls", log.LevelInfo},
+		{"dbx", log.LevelInfo},
+		{"http", log.LevelWarn},
+		{"http.client", log.LevelWarn},
+		{"httpx", log.LevelInfo},
+		{"cache", log.LevelInfo},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			logger := l
+			for _, name := range strings.Split(c.Name, ".") {
+				logger = logger.Named(name)
+			}
+			assert.Equal(t, c.Level, logger.GetLevel())
+		})
+	}
+
+	assert.Equal(t, log.LevelInfo, l.GetLevel())
+
+	l.Named("db").Debug("query")
+	l.Named("http").Info("request")
+	l.Named("cache").Debug("miss")
+
+	assert.Equal(t, 1, len(h.Entries))
+	assert.Equal(t, "query", h.Entries[0].GetMessage())
+
+	assert.NoError(t, l.SetLevelOverrides(""))
+	assert.Equal(t, log.LevelInfo, l.Named("db").GetLevel())
+}
+
+func TestLogger_SetLevelOverrides_invalid(t *testing.T) {
+	l := &log.Logger{
+		Level: log.LevelInfo,
+	}
+
+	assert.Error(t, l.SetLevelOverrides("db"))
+	assert.Error(t, l.SetLevelOverrides("=debug"))
+	assert.Error(t, l.SetLevelOverrides("db=loud"))
+}
@@ -0,0 +1,70 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/json"
+	"github.com/sayden/log/handlers/logfmt"
+	"github.com/sayden/log/handlers/memory"
+)
+
+func TestLogger_OrderedFields(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler:       h,
+		Level:         log.LevelInfo,
+		OrderedFields: true,
+	}
+
+	l.AddHook(log.HookFunc(func(e *log.Entry) error {
+		e.Fields["host"] = "sloth"
+		return nil
+	}))
+
+	l.WithField("request_id", "abc").
+		WithFields(log.Fields{"user": "tobi", "file": "sloth.png"}).
+		WithField("request_id", "def").
+		With(log.Int("size", 1024)).
+		Info("upload")
+
+	e := h.Entries[0]
+	assert.Equal(t, []string{"request_id", "file", "user", "size", "host"}, e.GetFieldNames())
+	assert.Equal(t, "def", e.GetFields()["request_id"])
+}
+
+func TestLogger_OrderedFields_disabled(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.WithField("user", "tobi").WithField("file", "sloth.png").Info("upload")
+
+	assert.Equal(t, []string{"file", "user"}, h.Entries[0].GetFieldNames())
+}
+
+func TestLogger_OrderedFields_handlers(t *testing.T) {
+	var j, lf bytes.Buffer
+
+	for _, h := range []log.Handler{json.New(&j), logfmt.New(&lf)} {
+		l := &log.Logger{
+			Handler:       h,
+			Level:         log.LevelInfo,
+			OrderedFields: true,
+			Clock:         log.NewFakeClock(time.Unix(0, 0).UTC()),
+		}
+
+		l.WithField("request_id", "abc").WithField("file", "sloth.png").Info("upload")
+	}
+
+	assert.Equal(t, `{"fields":{"request_id":"abc","file":"sloth.png"},"level":"info","timestamp":"1970-01-01T00:00:00Z","message":"upload"}`+"\n", j.String())
+	assert.Equal(t, "timestamp=1970-01-01T00:00:00Z level=info message=upload request_id=abc file=sloth.png\n", lf.String())
+}
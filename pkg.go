@@ -1,25 +1,30 @@
 package log
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 // singletons ftw?
 var Log Interface = &Logger{
-	Handler: HandlerFunc(handleStdLog),
-	Level:   LevelInfo,
-	t:       &prom{counterMetrics: make(map[string]*prometheus.CounterVec)},
+	Handler:   HandlerFunc(handleStdLog),
+	Level:     LevelInfo,
+	Telemetry: TelemetryPrometheus(),
 }
 
-// SetHandler sets the handler. This is not thread-safe.
-// The default handler outputs to the stdlib log.
+// SetHandler sets the handler. The default handler outputs to the stdlib log.
 func SetHandler(h Handler) {
 	if logger, ok := Log.(*Logger); ok {
-		logger.Handler = h
+		logger.SwapHandler(h)
 	}
 }
 
+// SetTelemetry sets the telemetry.
 func SetTelemetry(t Telemetry) {
 	if logger, ok := Log.(*Logger); ok {
-		logger.t = t
+		logger.SetTelemetry(t)
 	}
 }
 
@@ -33,22 +38,79 @@ func SetNamespace(s string) {
 	Log.GetTelemetry().SetNamespace(s)
 }
 
-// SetLevel sets the log level. This is not thread-safe.
+// SetLevel sets the log level.
 func SetLevel(l Level) {
 	if logger, ok := Log.(*Logger); ok {
-		logger.Level = l
+		logger.SetLevel(l)
+	}
+}
+
+// SetVerbosity sets the verbosity used by V.
+func SetVerbosity(v int) {
+	if logger, ok := Log.(*Logger); ok {
+		logger.SetVerbosity(v)
 	}
 }
 
-// SetLevelFromString sets the log level from a string, panicing when invalid. This is not thread-safe.
+// SetLevelFromString sets the log level from a string, panicing when invalid.
 func SetLevelFromString(s string) {
 	if logger, ok := Log.(*Logger); ok {
-		logger.Level = MustParseLevel(s)
+		logger.SetLevel(MustParseLevel(s))
+	}
+}
+
+// SetLevelOverrides sets levels for named loggers, such as "db.*=debug,http=warn".
+func SetLevelOverrides(spec string) error {
+	if logger, ok := Log.(*Logger); ok {
+		return logger.SetLevelOverrides(spec)
+	}
+
+	return nil
+}
+
+// AddHook adds a hook run for entries of `levels`, or of every level.
+func AddHook(h Hook, levels ...Level) {
+	if logger, ok := Log.(*Logger); ok {
+		logger.AddHook(h, levels...)
+	}
+}
+
+// Flush flushes the buffered entries of every handler.
+func Flush(ctx context.Context) error {
+	if logger, ok := Log.(*Logger); ok {
+		return logger.Flush(ctx)
 	}
+
+	return nil
+}
+
+// Close flushes and closes every handler, call it once at shutdown.
+func Close(ctx context.Context) error {
+	if logger, ok := Log.(*Logger); ok {
+		return logger.Close(ctx)
+	}
+
+	return nil
+}
+
+// Enabled returns whether messages at `level` are logged.
+func Enabled(level Level) bool {
+	return Log.Enabled(level)
 }
 
-func WithTags(tag ...string) Interface {
-	return Log.GetTelemetry().WithTags(tag...)
+// V returns a new entry which only logs when `n` is at most the verbosity.
+func V(n int) Interface {
+	return Log.V(n)
+}
+
+// WithTags returns a new entry with `tags` used by Inc.
+func WithTags(tags ...string) Interface {
+	return Log.WithTags(tags...)
+}
+
+// Inc increments the counter `name` by `value`, returning a new entry.
+func Inc(name string, value float64) Interface {
+	return Log.Inc(name, value)
 }
 
 // WithFields returns a new entry with `fields` set.
@@ -61,11 +123,21 @@ func WithField(key string, value interface{}) Interface {
 	return Log.WithField(key, value)
 }
 
+// With returns a new entry with the typed `fields` set.
+func With(fields ...Field) Interface {
+	return Log.With(fields...)
+}
+
 // WithError returns a new entry with the "error" set to `err`.
 func WithError(err error) Interface {
 	return Log.WithError(err)
 }
 
+// WithContext returns a new entry carrying `ctx`.
+func WithContext(ctx context.Context) Interface {
+	return Log.WithContext(ctx)
+}
+
 // Debug level message.
 func Debug(msg string) {
 	Log.Debug(msg)
@@ -76,6 +148,11 @@ func Info(msg string) {
 	Log.Info(msg)
 }
 
+// Notice level message.
+func Notice(msg string) {
+	Log.Notice(msg)
+}
+
 // Warn level message.
 func Warn(msg string) {
 	Log.Warn(msg)
@@ -86,11 +163,26 @@ func Error(msg string) {
 	Log.Error(msg)
 }
 
+// Critical level message.
+func Critical(msg string) {
+	Log.Critical(msg)
+}
+
+// Panic level message, followed by a panic.
+func Panic(msg string) {
+	Log.Panic(msg)
+}
+
 // Fatal level message, followed by an exit.
 func Fatal(msg string) {
 	Log.Fatal(msg)
 }
 
+// Logf formatted message at `level`.
+func Logf(level Level, msg string, v ...interface{}) {
+	Log.Logf(level, msg, v...)
+}
+
 // Debugf level formatted message.
 func Debugf(msg string, v ...interface{}) {
 	Log.Debugf(msg, v...)
@@ -101,6 +193,11 @@ func Infof(msg string, v ...interface{}) {
 	Log.Infof(msg, v...)
 }
 
+// Noticef level formatted message.
+func Noticef(msg string, v ...interface{}) {
+	Log.Noticef(msg, v...)
+}
+
 // Warnf level formatted message.
 func Warnf(msg string, v ...interface{}) {
 	Log.Warnf(msg, v...)
@@ -111,13 +208,74 @@ func Errorf(msg string, v ...interface{}) {
 	Log.Errorf(msg, v...)
 }
 
+// Criticalf level formatted message.
+func Criticalf(msg string, v ...interface{}) {
+	Log.Criticalf(msg, v...)
+}
+
+// Panicf level formatted message, followed by a panic.
+func Panicf(msg string, v ...interface{}) {
+	Log.Panicf(msg, v...)
+}
+
 // Fatalf level formatted message, followed by an exit.
 func Fatalf(msg string, v ...interface{}) {
 	Log.Fatalf(msg, v...)
 }
 
+// LogKV message at `level` with the alternating keys and values of `kv`.
+func LogKV(level Level, msg string, kv ...interface{}) {
+	Log.LogKV(level, msg, kv...)
+}
+
+// DebugKV level message with key-value fields.
+func DebugKV(msg string, kv ...interface{}) {
+	Log.DebugKV(msg, kv...)
+}
+
+// InfoKV level message with key-value fields.
+func InfoKV(msg string, kv ...interface{}) {
+	Log.InfoKV(msg, kv...)
+}
+
+// NoticeKV level message with key-value fields.
+func NoticeKV(msg string, kv ...interface{}) {
+	Log.NoticeKV(msg, kv...)
+}
+
+// WarnKV level message with key-value fields.
+func WarnKV(msg string, kv ...interface{}) {
+	Log.WarnKV(msg, kv...)
+}
+
+// ErrorKV level message with key-value fields.
+func ErrorKV(msg string, kv ...interface{}) {
+	Log.ErrorKV(msg, kv...)
+}
+
+// CriticalKV level message with key-value fields.
+func CriticalKV(msg string, kv ...interface{}) {
+	Log.CriticalKV(msg, kv...)
+}
+
+// PanicKV level message with key-value fields, followed by a panic.
+func PanicKV(msg string, kv ...interface{}) {
+	Log.PanicKV(msg, kv...)
+}
+
+// FatalKV level message with key-value fields, followed by an exit.
+func FatalKV(msg string, kv ...interface{}) {
+	Log.FatalKV(msg, kv...)
+}
+
 // Trace returns a new entry with a Stop method to fire off
 // a corresponding completion log, useful with defer.
 func Trace(msg string) Interface {
 	return Log.Trace(msg)
 }
+
+// TraceSlow returns a new entry with a Stop method firing off a
+// completion log only when it takes `threshold` or longer, or fails.
+func TraceSlow(msg string, threshold time.Duration) Interface {
+	return Log.TraceSlow(msg, threshold)
+}
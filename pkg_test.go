@@ -2,6 +2,7 @@ package log_test
 
 import (
 	"errors"
+	"sync"
 	"testing"
 
 	"github.com/sayden/log"
@@ -43,6 +44,38 @@ func TestFielder(t *testing.T) {
 	assert.Equal(t, log.Fields{"name": "Tobi", "age": 3}, e.GetFields())
 }
 
+func TestSetHandler_concurrent(t *testing.T) {
+	a := memory.New()
+	b := memory.New()
+	log.SetHandler(a)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				log.Info("upload")
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		if i%2 == 0 {
+			log.SetHandler(b)
+			log.SetLevel(log.LevelDebug)
+		} else {
+			log.SetHandler(a)
+			log.SetLevelFromString("info")
+		}
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1000, len(a.Entries)+len(b.Entries))
+}
+
 // Unstructured logging is supported, but not recommended since it is hard to query.
 func Example_unstructured() {
 	log.Infof("%s logged in", "Tobi")
@@ -70,7 +103,11 @@ func Example_multipleFields() {
 
 // Trace can be used to simplify logging of start and completion events,
 // for example an upload which may fail.
-func Example_trace() (err error) {
-	defer log.Trace("upload").Stop(&err)
-	return nil
+func Example_trace() {
+	upload := func() (err error) {
+		defer log.Trace("upload").Stop(&err)
+		return nil
+	}
+
+	upload()
 }
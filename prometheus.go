@@ -1,12 +1,15 @@
 package log
 
 import (
+	"fmt"
+	"sync"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type prom struct {
 	id             string
-	tags           []string
+	mu             sync.RWMutex
 	counterMetrics map[string]*prometheus.CounterVec
 }
 
@@ -14,20 +17,30 @@ func TelemetryPrometheus() Telemetry {
 	return &prom{counterMetrics: make(map[string]*prometheus.CounterVec)}
 }
 
-func (p *prom) WithTags(tags ...string) Interface {
-	p.tags = tags
+func (p *prom) Inc(name string, value float64, tags ...string) error {
+	p.mu.RLock()
+	counter, ok := p.counterMetrics[name]
+	p.mu.RUnlock()
 
-	return Log
-}
+	if !ok {
+		return fmt.Errorf("unknown counter %q", name)
+	}
 
-func (p *prom) Inc(name string, value float64) Interface {
-	p.counterMetrics[name].WithLabelValues(p.tags...).Add(value)
+	c, err := counter.GetMetricWithLabelValues(tags...)
+	if err != nil {
+		return err
+	}
 
-	return Log
+	c.Add(value)
+
+	return nil
 }
 
 func (p *prom) SetPrometheusInc(name string, counter *prometheus.CounterVec) {
+	p.mu.Lock()
 	p.counterMetrics[name] = counter
+	p.mu.Unlock()
+
 	prometheus.MustRegister(counter)
 }
 
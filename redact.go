@@ -0,0 +1,247 @@
+package log
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// RedactMode is what a Redactor does with the values it matches.
+type RedactMode int
+
+// Redact modes.
+const (
+	// RedactMask replaces the value with the Mask.
+	RedactMask RedactMode = iota
+
+	// RedactHash replaces the value with the hex SHA-256 of the Salt and
+	// the value, so equal values can still be correlated.
+	RedactHash
+
+	// RedactDrop removes the field.
+	RedactDrop
+)
+
+// DefaultMask is the Mask used when none is set.
+const DefaultMask = "[REDACTED]"
+
+// maxRedactDepth bounds the recursion into nested values.
+const maxRedactDepth = 16
+
+// Redactor redacts the fields whose keys match Keys or Pattern, at any
+// depth of nested maps, slices and structs. Struct fields match on their
+// json name. Nested values are only copied when something is redacted.
+//
+// It implements Hook, so it may be added to a Logger with AddHook to
+// apply to every handler, or wrap some handlers with handlers/redact.
+type Redactor struct {
+	// Keys are matched case-insensitively, and may be globs such as
+	// "*_token".
+	Keys []string
+
+	// Pattern, if set, redacts the keys it matches too.
+	Pattern *regexp.Regexp
+
+	Mode RedactMode
+	Mask string
+	Salt string
+}
+
+// Fire implements Hook.
+func (r *Redactor) Fire(e *Entry) error {
+	e.Fields = r.Redact(e.Fields)
+	return nil
+}
+
+// Redact returns `fields` with the matching values redacted, or
+// `fields` itself when none match.
+func (r *Redactor) Redact(fields Fields) Fields {
+	m, ok := r.redactMap(reflect.ValueOf(fields), 0)
+	if !ok {
+		return fields
+	}
+
+	return Fields(m)
+}
+
+// match returns whether `key` is redacted. Dotted keys, such as the
+// ones expanded from Valuers, also match on their last segment.
+func (r *Redactor) match(key string) bool {
+	if r.matchKey(key) {
+		return true
+	}
+
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		return r.matchKey(key[i+1:])
+	}
+
+	return false
+}
+
+// matchKey returns whether `key` matches Keys or Pattern.
+func (r *Redactor) matchKey(key string) bool {
+	k := strings.ToLower(key)
+
+	for _, p := range r.Keys {
+		if ok, _ := path.Match(strings.ToLower(p), k); ok {
+			return true
+		}
+	}
+
+	return r.Pattern != nil && r.Pattern.MatchString(key)
+}
+
+// replace returns the redacted form of `v`.
+func (r *Redactor) replace(v interface{}) interface{} {
+	if r.Mode == RedactHash {
+		sum := sha256.Sum256([]byte(r.Salt + fmt.Sprint(v)))
+		return hex.EncodeToString(sum[:])
+	}
+
+	if r.Mask == "" {
+		return DefaultMask
+	}
+
+	return r.Mask
+}
+
+// redact returns `v` with its nested matching values redacted, and
+// whether anything was.
+func (r *Redactor) redact(v interface{}, depth int) (interface{}, bool) {
+	if v == nil || depth > maxRedactDepth {
+		return v, false
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() || opaque(v) {
+			return v, false
+		}
+		return r.redact(rv.Elem().Interface(), depth+1)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v, false
+		}
+		if m, ok := r.redactMap(rv, depth); ok {
+			return m, true
+		}
+		return v, false
+	case reflect.Struct:
+		if opaque(v) {
+			return v, false
+		}
+		return r.redactStruct(rv, depth)
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return v, false
+		}
+		return r.redactSlice(rv, depth)
+	default:
+		return v, false
+	}
+}
+
+// redactMap redacts a map keyed by strings.
+func (r *Redactor) redactMap(rv reflect.Value, depth int) (map[string]interface{}, bool) {
+	m := make(map[string]interface{}, rv.Len())
+	changed := false
+
+	iter := rv.MapRange()
+	for iter.Next() {
+		k := iter.Key().String()
+		v := iter.Value().Interface()
+
+		if r.match(k) {
+			changed = true
+			if r.Mode != RedactDrop {
+				m[k] = r.replace(v)
+			}
+			continue
+		}
+
+		v, ok := r.redact(v, depth+1)
+		changed = changed || ok
+		m[k] = v
+	}
+
+	return m, changed
+}
+
+// redactStruct redacts the exported fields of a struct, returning it as
+// a map keyed by their json names.
+func (r *Redactor) redactStruct(rv reflect.Value, depth int) (interface{}, bool) {
+	t := rv.Type()
+	m := make(map[string]interface{}, t.NumField())
+	changed := false
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		k := f.Name
+		if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag == "-" {
+			continue
+		} else if tag != "" {
+			k = tag
+		}
+
+		v := rv.Field(i).Interface()
+
+		if r.match(k) {
+			changed = true
+			if r.Mode != RedactDrop {
+				m[k] = r.replace(v)
+			}
+			continue
+		}
+
+		v, ok := r.redact(v, depth+1)
+		changed = changed || ok
+		m[k] = v
+	}
+
+	if !changed {
+		return rv.Interface(), false
+	}
+
+	return m, true
+}
+
+// redactSlice redacts the elements of a slice or array.
+func (r *Redactor) redactSlice(rv reflect.Value, depth int) (interface{}, bool) {
+	s := make([]interface{}, rv.Len())
+	changed := false
+
+	for i := range s {
+		v, ok := r.redact(rv.Index(i).Interface(), depth+1)
+		changed = changed || ok
+		s[i] = v
+	}
+
+	if !changed {
+		return rv.Interface(), false
+	}
+
+	return s, true
+}
+
+// opaque returns whether `v` encodes itself, such as time.Time and
+// errors, so its fields are not walked.
+func opaque(v interface{}) bool {
+	switch v.(type) {
+	case json.Marshaler, encoding.TextMarshaler, error:
+		return true
+	default:
+		return false
+	}
+}
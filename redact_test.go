@@ -0,0 +1,131 @@
+package log_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/memory"
+)
+
+type credentials struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+	internal string
+}
+
+func TestRedactor(t *testing.T) {
+	r := &log.Redactor{
+		Keys:    []string{"password", "Authorization", "*_token"},
+		Pattern: regexp.MustCompile(`^secret`),
+	}
+
+	f := r.Redact(log.Fields{
+		"user":          "tobi",
+		"password":      "hunter2",
+		"refresh_token": "abc",
+		"secretKey":     "xyz",
+		"headers": map[string][]string{
+			"authorization": {"Bearer abc"},
+			"accept":        {"*/*"},
+		},
+		"login": credentials{User: "tobi", Password: "hunter2"},
+		"logins": []interface{}{
+			&credentials{User: "loki", Password: "hunter3"},
+		},
+	})
+
+	assert.Equal(t, log.Fields{
+		"user":          "tobi",
+		"password":      log.DefaultMask,
+		"refresh_token": log.DefaultMask,
+		"secretKey":     log.DefaultMask,
+		"headers": map[string]interface{}{
+			"authorization": log.DefaultMask,
+			"accept":        []string{"*/*"},
+		},
+		"login": map[string]interface{}{
+			"user":     "tobi",
+			"password": log.DefaultMask,
+		},
+		"logins": []interface{}{
+			map[string]interface{}{
+				"user":     "loki",
+				"password": log.DefaultMask,
+			},
+		},
+	}, f)
+}
+
+func TestRedactor_unchanged(t *testing.T) {
+	r := &log.Redactor{Keys: []string{"password"}}
+
+	c := struct {
+		User string `json:"user"`
+	}{"tobi"}
+	f := r.Redact(log.Fields{"login": c, "nested": log.Fields{"user": "tobi"}})
+
+	assert.Equal(t, c, f["login"])
+	assert.Equal(t, log.Fields{"user": "tobi"}, f["nested"])
+}
+
+func TestRedactor_modes(t *testing.T) {
+	fields := log.Fields{"user": "tobi", "password": "hunter2"}
+
+	drop := &log.Redactor{Keys: []string{"password"}, Mode: log.RedactDrop}
+	assert.Equal(t, log.Fields{"user": "tobi"}, drop.Redact(fields))
+
+	mask := &log.Redactor{Keys: []string{"password"}, Mask: "***"}
+	assert.Equal(t, log.Fields{"user": "tobi", "password": "***"}, mask.Redact(fields))
+
+	hash := &log.Redactor{Keys: []string{"password"}, Mode: log.RedactHash}
+	a := hash.Redact(fields)["password"]
+	assert.Len(t, a, 64)
+	assert.Equal(t, a, hash.Redact(log.Fields{"password": "hunter2"})["password"])
+	assert.NotEqual(t, a, hash.Redact(log.Fields{"password": "hunter3"})["password"])
+
+	salted := &log.Redactor{Keys: []string{"password"}, Mode: log.RedactHash, Salt: "pepper"}
+	assert.NotEqual(t, a, salted.Redact(fields)["password"])
+}
+
+func TestRedactor_hook(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.AddHook(&log.Redactor{Keys: []string{"password"}})
+	l.WithFields(log.Fields{"user": "tobi", "password": "hunter2"}).Info("login")
+
+	assert.Equal(t, log.Fields{"user": "tobi", "password": log.DefaultMask}, h.Entries[0].GetFields())
+}
+
+type account struct {
+	User     string
+	Password string
+}
+
+func (a account) LogValue() interface{} {
+	return log.Fields{"user": a.User, "password": a.Password}
+}
+
+func TestRedactor_valuer(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	l.AddHook(&log.Redactor{Keys: []string{"password"}})
+	l.WithField("acct", account{User: "tobi", Password: "hunter2"}).Info("login")
+
+	assert.Equal(t, log.Fields{
+		"acct.user":     "tobi",
+		"acct.password": log.DefaultMask,
+	}, h.Entries[0].GetFields())
+}
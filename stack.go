@@ -1,8 +1,159 @@
 package log
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
 
 // stackTracer interface.
 type stackTracer interface {
 	StackTrace() errors.StackTrace
 }
+
+// pkgPrefix is the prefix of the function names in this package.
+var pkgPrefix = reflect.TypeOf(Frame{}).PkgPath() + "."
+
+// Frame is a location in the source code.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String returns the file and line, with the file relative to its package.
+func (f Frame) String() string {
+	dir, file := filepath.Split(f.File)
+	return fmt.Sprintf("%s:%d", filepath.Join(filepath.Base(dir), file), f.Line)
+}
+
+// ErrorLayer is an error of an ErrorChain.
+type ErrorLayer struct {
+	Type    string  `json:"type"`
+	Message string  `json:"message"`
+	Stack   []Frame `json:"stack,omitempty"`
+}
+
+// ErrorChain is the chain of wrapped errors, outermost first.
+type ErrorChain []ErrorLayer
+
+// String returns the types of the errors in the chain.
+func (c ErrorChain) String() string {
+	var types []string
+	for _, l := range c {
+		types = append(types, l.Type)
+	}
+	return strings.Join(types, " > ")
+}
+
+// WriteIndented writes the errors of the chain and their stacks on
+// multiple lines, indented by `indent` spaces.
+func (c ErrorChain) WriteIndented(w io.Writer, indent int) error {
+	for _, l := range c {
+		if _, err := fmt.Fprintf(w, "%*s%s: %s\n", indent, "", l.Type, l.Message); err != nil {
+			return err
+		}
+
+		for _, f := range l.Stack {
+			if _, err := fmt.Fprintf(w, "%*s%s\n%*s%s:%d\n", indent+4, "", f.Function, indent+8, "", f.File, f.Line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// errorTypes returns the types of `errs`, as in ErrorChain.String.
+func errorTypes(errs []error) string {
+	types := make([]string, len(errs))
+	for i, err := range errs {
+		types[i] = fmt.Sprintf("%T", err)
+	}
+	return strings.Join(types, " > ")
+}
+
+// newErrorChain returns the ErrorChain of the unwrapped `errs`.
+func newErrorChain(errs []error) ErrorChain {
+	var c ErrorChain
+
+	for _, err := range errs {
+		l := ErrorLayer{
+			Type:    fmt.Sprintf("%T", err),
+			Message: err.Error(),
+		}
+
+		if s, ok := err.(stackTracer); ok {
+			for _, f := range s.StackTrace() {
+				pc := uintptr(f) - 1
+				fn := runtime.FuncForPC(pc)
+				if fn == nil {
+					continue
+				}
+
+				file, line := fn.FileLine(pc)
+				l.Stack = append(l.Stack, Frame{
+					Function: fn.Name(),
+					File:     file,
+					Line:     line,
+				})
+			}
+		}
+
+		c = append(c, l)
+	}
+
+	return c
+}
+
+// unwrap returns `err` followed by the errors it wraps, depth-first,
+// following both Unwrap() error and the Unwrap() []error of errors.Join.
+func unwrap(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	errs := []error{err}
+
+	switch v := err.(type) {
+	case interface{ Unwrap() error }:
+		errs = append(errs, unwrap(v.Unwrap())...)
+	case interface{ Unwrap() []error }:
+		for _, err := range v.Unwrap() {
+			errs = append(errs, unwrap(err)...)
+		}
+	}
+
+	return errs
+}
+
+// caller returns the first frame outside of this package and the stdlib
+// log package, skipping `skip` more frames for wrappers around it.
+func caller(skip int) Frame {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+
+	for {
+		f, more := frames.Next()
+
+		if !strings.HasPrefix(f.Function, pkgPrefix) && !strings.HasPrefix(f.Function, "log.") {
+			if skip == 0 {
+				return Frame{
+					Function: f.Function,
+					File:     f.File,
+					Line:     f.Line,
+				}
+			}
+			skip--
+		}
+
+		if !more {
+			return Frame{}
+		}
+	}
+}
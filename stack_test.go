@@ -0,0 +1,95 @@
+package log_test
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_AddCaller(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler:   h,
+		Level:     log.LevelInfo,
+		AddCaller: true,
+	}
+
+	_, file, line, _ := runtime.Caller(0)
+	l.Info("upload")
+	l.WithField("file", "sloth.png").Infof("upload %s", "complete")
+	l.Named("db").Warn("slow")
+
+	assert.Equal(t, 3, len(h.Entries))
+
+	for i, e := range h.Entries {
+		f, ok := e.GetFields()["caller"].(log.Frame)
+		assert.True(t, ok)
+		assert.Equal(t, file, f.File)
+		assert.Equal(t, line+i+1, f.Line)
+		assert.Equal(t, "github.com/sayden/log_test.TestLogger_AddCaller", f.Function)
+		assert.Equal(t, fmt.Sprintf("%s:%d", filepath.Join(filepath.Base(filepath.Dir(file)), "stack_test.go"), line+i+1), f.String())
+	}
+}
+
+func TestLogger_CallerSkip(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler:    h,
+		Level:      log.LevelInfo,
+		AddCaller:  true,
+		CallerSkip: 1,
+	}
+
+	warn := func(msg string) {
+		l.Warn(msg)
+	}
+
+	_, _, line, _ := runtime.Caller(0)
+	warn("slow")
+
+	f := h.Entries[0].GetFields()["caller"].(log.Frame)
+	assert.Equal(t, line+1, f.Line)
+}
+
+func TestAddCaller(t *testing.T) {
+	h := memory.New()
+	log.SetHandler(h)
+
+	logger := log.Log.(*log.Logger)
+	logger.AddCaller = true
+	defer func() { logger.AddCaller = false }()
+
+	_, _, line, _ := runtime.Caller(0)
+	log.Info("upload")
+
+	f := h.Entries[0].GetFields()["caller"].(log.Frame)
+	assert.Equal(t, line+1, f.Line)
+}
+
+func TestErrorChain_WriteIndented(t *testing.T) {
+	c := log.ErrorChain{
+		{Type: "*fmt.wrapError", Message: "uploading: boom"},
+		{Type: "*errors.fundamental", Message: "boom", Stack: []log.Frame{
+			{Function: "main.upload", File: "/src/main.go", Line: 12},
+		}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, c.WriteIndented(&buf, 2))
+
+	expected := `  *fmt.wrapError: uploading: boom
+  *errors.fundamental: boom
+      main.upload
+          /src/main.go:12
+`
+
+	assert.Equal(t, expected, buf.String())
+}
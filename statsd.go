@@ -1,16 +1,17 @@
 package log
 
 import (
+	"fmt"
 	"log"
+	"math"
 
 	statsd_client "github.com/DataDog/datadog-go/statsd"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type statsd struct {
-	id   string
-	tags []string
-	c    *statsd_client.Client
+	id string
+	c  *statsd_client.Client
 }
 
 func TelemetryStatsD() Telemetry {
@@ -24,17 +25,14 @@ func TelemetryStatsD() Telemetry {
 	}
 }
 
-func (p *statsd) WithTags(tags ...string) Interface {
-	p.tags = tags
-	return Log
-}
-
-func (p *statsd) Inc(name string, value float64) Interface {
-	if err := p.c.Incr(name, nil, value); err != nil {
-		Error(err.Error())
+// Inc implements Telemetry. StatsD counters are integers, so fractional
+// values are rejected instead of truncated.
+func (p *statsd) Inc(name string, value float64, tags ...string) error {
+	if value != math.Trunc(value) {
+		return fmt.Errorf("statsd: counter %s incremented by %v, which is not an integer", name, value)
 	}
 
-	return Log
+	return p.c.Count(name, int64(value), tags, 1)
 }
 
 func (p *statsd) SetPrometheusInc(name string, counter *prometheus.CounterVec) {
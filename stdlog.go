@@ -0,0 +1,77 @@
+package log
+
+import (
+	stdlog "log"
+	"strings"
+	"sync/atomic"
+)
+
+// stdOutput holds the *stdlog.Logger used while RedirectStdLog is in
+// effect, so this package does not write its own output back into Log.
+var stdOutput atomic.Value
+
+// std returns the stdlib logger this package writes its own output to.
+func std() *stdlog.Logger {
+	if l, ok := stdOutput.Load().(*stdlog.Logger); ok && l != nil {
+		return l
+	}
+
+	return stdlog.Default()
+}
+
+// StdLog returns the stdlib logger this package and its handlers write
+// their own diagnostics to. It is never redirected to Log by
+// RedirectStdLog, so a handler may use it without logging to itself.
+func StdLog() *stdlog.Logger {
+	return std()
+}
+
+// stdWriter logs every write as an entry.
+type stdWriter struct {
+	log   Interface
+	level Level
+}
+
+// Write implements io.Writer.
+func (w *stdWriter) Write(b []byte) (int, error) {
+	l := w.log
+	if l == nil {
+		l = Log
+	}
+
+	l.Log(w.level, strings.TrimSuffix(string(b), "\n"))
+	return len(b), nil
+}
+
+// NewStdLogger returns a stdlib *log.Logger logging each of its lines to
+// `l` at `level`, such as for net/http.Server.ErrorLog.
+func NewStdLogger(l Interface, level Level) *stdlog.Logger {
+	return stdlog.New(&stdWriter{log: l, level: level}, "", 0)
+}
+
+// RedirectStdLog makes the stdlib log package write to Log at `level`,
+// returning a function which restores its previous output. While
+// redirected, the default handler writes to the previous output.
+// Redirecting again only changes the level, until it is restored.
+func RedirectStdLog(level Level) func() {
+	flags := stdlog.Flags()
+	prefix := stdlog.Prefix()
+	w := stdlog.Writer()
+	prev, _ := stdOutput.Load().(*stdlog.Logger)
+
+	// already redirected, so keep writing to the output it saved
+	if _, ok := w.(*stdWriter); !ok || prev == nil {
+		stdOutput.Store(stdlog.New(w, prefix, flags))
+	}
+
+	stdlog.SetOutput(&stdWriter{level: level})
+	stdlog.SetFlags(0)
+	stdlog.SetPrefix("")
+
+	return func() {
+		stdlog.SetOutput(w)
+		stdlog.SetFlags(flags)
+		stdlog.SetPrefix(prefix)
+		stdOutput.Store(prev)
+	}
+}
@@ -0,0 +1,90 @@
+package log
+
+import (
+	"bytes"
+	stdlog "log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStdLogger(t *testing.T) {
+	var entries []Interface
+
+	l := &Logger{
+		Handler: HandlerFunc(func(e Interface) error {
+			entries = append(entries, e)
+			return nil
+		}),
+		Level: LevelInfo,
+	}
+
+	std := NewStdLogger(l.WithField("app", "uploads"), LevelWarn)
+	std.Printf("http: TLS handshake error from %s", "127.0.0.1")
+
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, LevelWarn, entries[0].GetLevel())
+	assert.Equal(t, "http: TLS handshake error from 127.0.0.1", entries[0].GetMessage())
+	assert.Equal(t, "uploads", entries[0].GetFields()["app"])
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	var buf bytes.Buffer
+	w := stdlog.Writer()
+	stdlog.SetOutput(&buf)
+	defer stdlog.SetOutput(w)
+
+	var entries []Interface
+
+	logger := Log.(*Logger)
+	prev := logger.SwapHandler(HandlerFunc(func(e Interface) error {
+		entries = append(entries, e)
+		return handleStdLog(e)
+	}))
+	defer logger.SwapHandler(prev)
+
+	restore := RedirectStdLog(LevelError)
+	stdlog.Print("boom")
+	restore()
+
+	stdlog.Print("restored")
+
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, LevelError, entries[0].GetLevel())
+	assert.Equal(t, "boom", entries[0].GetMessage())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Contains(t, lines[0], "error boom")
+	assert.Contains(t, lines[1], "restored")
+}
+
+func TestRedirectStdLog_twice(t *testing.T) {
+	var buf bytes.Buffer
+	w := stdlog.Writer()
+	stdlog.SetOutput(&buf)
+	defer stdlog.SetOutput(w)
+
+	logger := Log.(*Logger)
+	prev := logger.SwapHandler(HandlerFunc(handleStdLog))
+	defer logger.SwapHandler(prev)
+
+	restoreError := RedirectStdLog(LevelError)
+	restoreWarn := RedirectStdLog(LevelWarn)
+	stdlog.Print("slow")
+	restoreWarn()
+
+	stdlog.Print("boom")
+	restoreError()
+
+	stdlog.Print("restored")
+
+	assert.Equal(t, &buf, stdlog.Writer())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Contains(t, lines[0], "warn slow")
+	assert.Contains(t, lines[1], "error boom")
+	assert.Contains(t, lines[2], "restored")
+}
@@ -0,0 +1,53 @@
+package log
+
+import "reflect"
+
+// Valuer is implemented by types controlling how they are logged as a
+// field value, such as a User logging only its ID. LogValue is called
+// when the entry is logged, before any handler sees it, so the value is
+// rendered the same by all of them.
+//
+// When LogValue returns a Fielder, such as Fields, the value expands
+// into one field per key, named "key.sub".
+type Valuer interface {
+	LogValue() interface{}
+}
+
+// maxValuerDepth bounds LogValue returning further Valuers.
+const maxValuerDepth = 8
+
+// setField sets `key` to the value `v` in `f`, evaluating Lazy values
+// and expanding Valuers. New keys are appended to `names` unless nil.
+func setField(f Fields, names *[]string, key string, v interface{}) {
+	v = eval(v)
+
+	for i := 0; i < maxValuerDepth; i++ {
+		lv, ok := v.(Valuer)
+		if !ok || isNilPtr(v) {
+			break
+		}
+
+		v = eval(lv.LogValue())
+
+		if fl, ok := v.(Fielder); ok {
+			sub := fl.Fields()
+			for _, k := range sub.Names() {
+				setField(f, names, key+"."+k, sub[k])
+			}
+			return
+		}
+	}
+
+	if _, ok := f[key]; !ok && names != nil {
+		*names = append(*names, key)
+	}
+
+	f[key] = v
+}
+
+// isNilPtr returns whether `v` is a nil pointer, which may not be
+// safe to call methods on.
+func isNilPtr(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
@@ -0,0 +1,74 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sayden/log"
+	"github.com/sayden/log/handlers/json"
+	"github.com/sayden/log/handlers/logfmt"
+	"github.com/sayden/log/handlers/memory"
+)
+
+type user struct {
+	ID    int
+	Email string
+}
+
+func (u *user) LogValue() interface{} {
+	return u.ID
+}
+
+type upload struct {
+	Name string
+	Size int
+	User *user
+}
+
+func (u upload) LogValue() interface{} {
+	return log.Fields{"name": u.Name, "size": u.Size, "user": u.User}
+}
+
+func TestValuer(t *testing.T) {
+	h := memory.New()
+
+	l := &log.Logger{
+		Handler: h,
+		Level:   log.LevelInfo,
+	}
+
+	u := &user{ID: 5, Email: "tobi@example.com"}
+	var nilUser *user
+
+	l.WithField("user", u).
+		WithField("nobody", nilUser).
+		WithField("upload", upload{Name: "sloth.png", Size: 1024, User: u}).
+		Info("upload")
+
+	assert.Equal(t, log.Fields{
+		"user":        5,
+		"nobody":      nilUser,
+		"upload.name": "sloth.png",
+		"upload.size": 1024,
+		"upload.user": 5,
+	}, h.Entries[0].GetFields())
+}
+
+func TestValuer_handlers(t *testing.T) {
+	var j, lf bytes.Buffer
+
+	for _, h := range []log.Handler{json.New(&j), logfmt.New(&lf)} {
+		l := &log.Logger{
+			Handler: h,
+			Level:   log.LevelInfo,
+		}
+
+		l.WithField("user", &user{ID: 5, Email: "tobi@example.com"}).Info("upload")
+	}
+
+	assert.Contains(t, j.String(), `"fields":{"user":5}`)
+	assert.Contains(t, lf.String(), `user=5`)
+	assert.NotContains(t, j.String()+lf.String(), "tobi@example.com")
+}